@@ -17,7 +17,9 @@ import (
 	"github.com/devusSs/twitch-kraken/internal/config"
 	"github.com/devusSs/twitch-kraken/internal/database/postgres"
 	"github.com/devusSs/twitch-kraken/internal/diagnosis"
+	"github.com/devusSs/twitch-kraken/internal/helix"
 	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/devusSs/twitch-kraken/internal/spotify"
 	"github.com/devusSs/twitch-kraken/internal/system"
 	"github.com/devusSs/twitch-kraken/internal/updater"
 	"github.com/devusSs/twitch-kraken/internal/utils"
@@ -189,7 +191,7 @@ func main() {
 		log.Fatalf("[%s] Error parsing durations: %s\n", logging.ErrorSign, err.Error())
 	}
 
-	twitchDuration := time.Until(authtwitch.TokenExpiry.Add(twitchPreDuration))
+	twitchDuration := time.Until(authtwitch.TokenExpiry().Add(twitchPreDuration))
 	twitchAuthTicker := time.NewTicker(twitchDuration)
 	go func() {
 		for range twitchAuthTicker.C {
@@ -208,7 +210,7 @@ func main() {
 		log.Fatalf("[%s] Error parsing durations: %s\n", logging.ErrorSign, err.Error())
 	}
 
-	spotifyDuration := time.Until(authspotify.TokenExpiry.Add(spotifyPreDuration))
+	spotifyDuration := time.Until(authspotify.TokenExpiry().Add(spotifyPreDuration))
 	spotifyAuthTicker := time.NewTicker(spotifyDuration)
 	go func() {
 		for range spotifyAuthTicker.C {
@@ -238,25 +240,35 @@ func main() {
 
 	logging.WriteSuccess("Successfully migrated database tables")
 
-	// Init Gatekeeper.
-	gateKeeper := gatekeeper.InitGateKeeper(cfg.Twitch.BotOwner, svc)
+	// Helix API client, uses the tokens of the Twitch authentication.
+	helixClient := helix.New(cfg.TwitchAuth.HelixURL, cfg.TwitchAuth.ClientID, authtwitch.AccessToken, auth.RefreshTwitchTokensFunc)
 
-	if err := gateKeeper.LoadSettingsFromStore(); err != nil {
-		logging.WriteError(err)
-		os.Exit(1)
-	}
+	// Spotify API client, uses the tokens of the Spotify authentication.
+	spotifyClient := spotify.New(cfg.SpotifyAuth.APIURL, authspotify.AccessToken, auth.RefreshSpotifyTokensFunc)
 
-	if err := gateKeeper.StoreInitialSettings(); err != nil {
-		logging.WriteError(err)
-		os.Exit(1)
-	}
+	twitchBot := bot.New(cfg, svc, helixClient, spotifyClient)
 
-	logging.WriteSuccess("Initiated Gatekeeper")
+	// Init one Gatekeeper per channel.
+	for _, channel := range twitchBot.Channels {
+		gateKeeper := gatekeeper.InitGateKeeper(channel.Name, channel.Owner, svc)
+
+		if err := gateKeeper.LoadSettingsFromStore(); err != nil {
+			logging.WriteError(err)
+			os.Exit(1)
+		}
+
+		if err := gateKeeper.StoreInitialSettings(); err != nil {
+			logging.WriteError(err)
+			os.Exit(1)
+		}
+
+		channel.GateKeeper = gateKeeper
+	}
 
-	twitchBot := bot.New(cfg, svc)
+	logging.WriteSuccess(fmt.Sprintf("Initiated Gatekeeper for %d channel(s)", len(twitchBot.Channels)))
 
 	// Setup needed functions to handle Twitch events.
-	twitchBot.SetupHandleFuncs(gateKeeper, newAppVersion)
+	twitchBot.SetupHandleFuncs(newAppVersion)
 
 	// Handle the periodic update checks.
 	// Notify the Bot owner via Twitch chat if there is a new version available.
@@ -75,7 +75,7 @@ func DoSpotifyAuth(cfg *config.Config) {
 // Helper function which checks if we have an access token, refresh token and token expiry.
 // Should be used as time.Ticker function (like time.Afterfunc()) to keep running.
 func gotSpotifyAccessToken() bool {
-	return authspotify.AccessToken != "" && authspotify.RefreshToken != "" && !authspotify.TokenExpiry.IsZero()
+	return authspotify.AccessToken() != "" && authspotify.RefreshToken() != "" && !authspotify.TokenExpiry().IsZero()
 }
 
 // Helper function which actually shuts down the server and decrements the wait group.
@@ -92,11 +92,17 @@ func shutdownSpotifyAuth(srv *http.Server, wg *sync.WaitGroup) error {
 	return nil
 }
 
+// Serializes token refreshes, the refresh ticker and the Spotify client may refresh at the same time.
+var spotifyRefreshMu sync.Mutex
+
 // Function to refresh the token we got.
 func RefreshSpotifyTokensFunc() error {
+	spotifyRefreshMu.Lock()
+	defer spotifyRefreshMu.Unlock()
+
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
-	data.Set("refresh_token", authspotify.RefreshToken)
+	data.Set("refresh_token", authspotify.RefreshToken())
 
 	req, err := http.NewRequest(http.MethodPost, "https://accounts.spotify.com/api/token", strings.NewReader(data.Encode()))
 	if err != nil {
@@ -136,8 +142,7 @@ func RefreshSpotifyTokensFunc() error {
 	}
 
 	if refresh.AccessToken != "" && refresh.ExpiresIn != 0 {
-		authspotify.AccessToken = refresh.AccessToken
-		authspotify.TokenExpiry = time.Now().Local().Add(time.Second * time.Duration(refresh.ExpiresIn))
+		authspotify.SetTokens(refresh.AccessToken, refresh.RefreshToken, time.Now().Local().Add(time.Second*time.Duration(refresh.ExpiresIn)))
 		return nil
 	}
 
@@ -73,7 +73,7 @@ func DoTwitchAuth(cfg *config.Config) {
 // Helper function which checks if we have an access token, refresh token and token expiry.
 // Should be used as time.Ticker function (like time.Afterfunc()) to keep running.
 func gotTwitchAccessToken() bool {
-	return authtwitch.AccessToken != "" && authtwitch.RefreshToken != "" && !authtwitch.TokenExpiry.IsZero()
+	return authtwitch.AccessToken() != "" && authtwitch.RefreshToken() != "" && !authtwitch.TokenExpiry().IsZero()
 }
 
 // Helper function which actually shuts down the server and decrements the wait group.
@@ -90,11 +90,17 @@ func shutdownTwitchAuth(srv *http.Server, wg *sync.WaitGroup) error {
 	return nil
 }
 
+// Prevents concurrent refreshes, Twitch invalidates a refresh token once it has been used.
+var twitchRefreshMu sync.Mutex
+
 // Function to refresh the token we got.
 func RefreshTwitchTokensFunc() error {
+	twitchRefreshMu.Lock()
+	defer twitchRefreshMu.Unlock()
+
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
-	data.Set("refresh_token", authtwitch.RefreshToken)
+	data.Set("refresh_token", authtwitch.RefreshToken())
 	data.Set("client_id", authtwitch.ClientID)
 	data.Set("client_secret", authtwitch.ClientSecret)
 
@@ -130,8 +136,12 @@ func RefreshTwitchTokensFunc() error {
 	}
 
 	if refresh.AccessToken != "" && refresh.RefreshToken != "" {
-		authtwitch.AccessToken = refresh.AccessToken
-		authtwitch.RefreshToken = refresh.RefreshToken
+		var expiry time.Time
+		if refresh.ExpiresIn > 0 {
+			expiry = time.Now().Add(time.Duration(refresh.ExpiresIn) * time.Second)
+		}
+
+		authtwitch.SetTokens(refresh.AccessToken, refresh.RefreshToken, expiry)
 		return nil
 	}
 
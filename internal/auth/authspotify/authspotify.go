@@ -35,17 +35,56 @@ const (
 var (
 	ClientID     string
 	ClientSecret string
-	scopes       = []string{"user-read-playback-state", "user-read-currently-playing"}
+	scopes       = []string{"user-read-playback-state", "user-read-currently-playing", "user-read-recently-played", "user-modify-playback-state"}
 	redirectURL  string
 	oauth2Config *oauth2.Config
 	cookieSecret []byte
 	cookieStore  *sessions.CookieStore
 
-	AccessToken  string
-	RefreshToken string
-	TokenExpiry  time.Time
+	// Guards the tokens, they are refreshed while the bot uses them.
+	tokenMu      sync.RWMutex
+	accessToken  string
+	refreshToken string
+	tokenExpiry  time.Time
 )
 
+// Returns the current access token.
+func AccessToken() string {
+	tokenMu.RLock()
+	defer tokenMu.RUnlock()
+	return accessToken
+}
+
+// Returns the current refresh token.
+func RefreshToken() string {
+	tokenMu.RLock()
+	defer tokenMu.RUnlock()
+	return refreshToken
+}
+
+// Returns the expiry of the current access token.
+func TokenExpiry() time.Time {
+	tokenMu.RLock()
+	defer tokenMu.RUnlock()
+	return tokenExpiry
+}
+
+// Replaces the current tokens, e.g. after a refresh. An empty refresh token or zero expiry keeps the old one.
+//
+// Spotify does not always return a new refresh token on refresh.
+func SetTokens(access, refresh string, expiry time.Time) {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
+	accessToken = access
+	if refresh != "" {
+		refreshToken = refresh
+	}
+	if !expiry.IsZero() {
+		tokenExpiry = expiry
+	}
+}
+
 // Init function to prepare bot for Spotify token generation.
 func InitSpotifyAuth(cfg *config.Config) func(path string, handler handler) {
 	// Init Client ID & Client Secret.
@@ -235,9 +274,7 @@ func handleOAuth2Callback(w http.ResponseWriter, r *http.Request) (err error) {
 	// add the oauth token to session
 	session.Values[oauthTokenKey] = token
 
-	AccessToken = token.AccessToken
-	RefreshToken = token.RefreshToken
-	TokenExpiry = token.Expiry
+	SetTokens(token.AccessToken, token.RefreshToken, token.Expiry)
 
 	http.Redirect(w, r, "/spotify", http.StatusTemporaryRedirect)
 
@@ -277,8 +314,9 @@ type handler func(http.ResponseWriter, *http.Request) error
 
 // Refresh struct.
 type SpotifyRefreshStruct struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	Scope       string `json:"scope"`
-	ExpiresIn   int    `json:"expires_in"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"` // only set if Spotify rotated the refresh token
 }
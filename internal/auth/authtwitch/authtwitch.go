@@ -35,17 +35,52 @@ const (
 var (
 	ClientID     string
 	ClientSecret string
-	scopes       = []string{"channel:manage:broadcast", "moderator:manage:banned_users", "moderation:read", "moderator:manage:chat_settings", "user:edit"}
+	scopes       = []string{"channel:manage:broadcast", "moderator:manage:banned_users", "moderation:read", "moderator:manage:chat_settings", "user:edit", "channel:manage:polls", "channel:manage:predictions", "clips:edit", "moderator:read:followers", "moderator:manage:chat_messages"}
 	redirectURL  string
 	oauth2Config *oauth2.Config
 	cookieSecret []byte
 	cookieStore  *sessions.CookieStore
 
-	AccessToken  string
-	RefreshToken string
-	TokenExpiry  time.Time
+	// Guards the token state below, tokens may be refreshed while the bot uses them.
+	tokenMu      sync.RWMutex
+	accessToken  string
+	refreshToken string
+	tokenExpiry  time.Time
 )
 
+// Returns the current access token.
+func AccessToken() string {
+	tokenMu.RLock()
+	defer tokenMu.RUnlock()
+	return accessToken
+}
+
+// Returns the current refresh token.
+func RefreshToken() string {
+	tokenMu.RLock()
+	defer tokenMu.RUnlock()
+	return refreshToken
+}
+
+// Returns the expiry of the current access token.
+func TokenExpiry() time.Time {
+	tokenMu.RLock()
+	defer tokenMu.RUnlock()
+	return tokenExpiry
+}
+
+// Replaces the current tokens, e.g. after a refresh. A zero expiry keeps the old one.
+func SetTokens(access, refresh string, expiry time.Time) {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
+	accessToken = access
+	refreshToken = refresh
+	if !expiry.IsZero() {
+		tokenExpiry = expiry
+	}
+}
+
 // Init function to prepare bot for Twitch token generation.
 func InitTwitchAuth(cfg *config.Config) func(path string, handler handler) {
 	// Init Client ID & Client Secret.
@@ -235,9 +270,7 @@ func handleOAuth2Callback(w http.ResponseWriter, r *http.Request) (err error) {
 	// add the oauth token to session
 	session.Values[oauthTokenKey] = token
 
-	AccessToken = token.AccessToken
-	RefreshToken = token.RefreshToken
-	TokenExpiry = token.Expiry
+	SetTokens(token.AccessToken, token.RefreshToken, token.Expiry)
 
 	http.Redirect(w, r, "/twitch", http.StatusTemporaryRedirect)
 
@@ -279,6 +312,7 @@ type handler func(http.ResponseWriter, *http.Request) error
 type TwitchRefreshStruct struct {
 	AccessToken  string   `json:"access_token"`
 	RefreshToken string   `json:"refresh_token"`
+	ExpiresIn    int      `json:"expires_in"`
 	Scope        []string `json:"scope"`
 	TokenType    string   `json:"token_type"`
 }
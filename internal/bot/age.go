@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/template"
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/helix"
+	"github.com/devusSs/twitch-kraken/internal/utils"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Applies the user level and cooldown rules of custom commands to a built-in command.
+//
+// The command uses the default cooldown and mods bypass it. Returns the answer for chat if the command may not run.
+func (b *TwitchBot) checkBuiltinCommand(channel *Channel, message twitch.PrivateMessage, name string, level types.UserLevel) string {
+	if !channel.HasUserLevel(message, level) {
+		return "You are not allowed to use that command."
+	}
+
+	if channel.HasUserLevel(message, types.Moderator) {
+		return ""
+	}
+
+	remaining, _, blocked := b.cooldowns.Acquire(channel.Name, name, message.User.Name,
+		time.Duration(b.DefaultCooldown)*time.Second, 0)
+	if blocked {
+		return fmt.Sprintf("Command %s is ready in %s.", b.displayCommand(name), formatCooldown(remaining))
+	}
+
+	return ""
+}
+
+// Returns the login and display name of the specified user, or of the user who sent the message.
+func targetUser(message twitch.PrivateMessage, args []string) (string, string) {
+	if len(args) > 0 && args[0] != "" {
+		displayName := strings.TrimPrefix(args[0], "@")
+		return strings.ToLower(displayName), displayName
+	}
+
+	return message.User.Name, message.User.DisplayName
+}
+
+// Handles the built-in !uptime command, shows how long the stream has been live.
+//
+// expected format: !uptime
+func (b *TwitchBot) uptimeCommand(channel *Channel, message twitch.PrivateMessage) string {
+	if answer := b.checkBuiltinCommand(channel, message, "uptime", types.Anyone); answer != "" {
+		return answer
+	}
+
+	uptime, err := b.streamUptime(channel)
+	if errors.Is(err, template.ErrOffline) {
+		return fmt.Sprintf("%s is offline.", channel.Name)
+	}
+	if err != nil {
+		return fmt.Sprintf("Could not get the stream: %s", err.Error())
+	}
+
+	return fmt.Sprintf("%s has been live for %s.", channel.Name, utils.FormatDuration(uptime))
+}
+
+// Handles the built-in !followage command, shows how long the user or the specified user has been following.
+//
+// expected format: !followage (<user>)
+func (b *TwitchBot) followAgeCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if answer := b.checkBuiltinCommand(channel, message, "followage", types.Anyone); answer != "" {
+		return answer
+	}
+
+	login, displayName := targetUser(message, args)
+
+	if login == channel.Name {
+		return fmt.Sprintf("%s is the broadcaster.", displayName)
+	}
+
+	userID := message.User.ID
+	if login != message.User.Name {
+		user, err := b.Helix.GetUserByLogin(login)
+		if errors.Is(err, helix.ErrNotFound) {
+			return fmt.Sprintf("User %s does not exist.", displayName)
+		}
+		if err != nil {
+			return fmt.Sprintf("Could not get the user: %s", err.Error())
+		}
+		userID = user.ID
+		displayName = user.DisplayName
+	}
+
+	follower, err := b.Helix.GetChannelFollower(channel.ID(), userID)
+	if errors.Is(err, helix.ErrNotFound) {
+		return fmt.Sprintf("%s is not following %s.", displayName, channel.Name)
+	}
+	if err != nil {
+		return fmt.Sprintf("Could not get the follow: %s", err.Error())
+	}
+
+	return fmt.Sprintf("%s has been following %s for %s (since %s).", displayName, channel.Name,
+		utils.FormatSince(follower.FollowedAt, time.Now()), follower.FollowedAt.Format("2006-01-02"))
+}
+
+// Handles the built-in !accountage command, shows how old the Twitch account of the user or the specified user is.
+//
+// expected format: !accountage (<user>)
+func (b *TwitchBot) accountAgeCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if answer := b.checkBuiltinCommand(channel, message, "accountage", types.Anyone); answer != "" {
+		return answer
+	}
+
+	login, displayName := targetUser(message, args)
+
+	user, err := b.Helix.GetUserByLogin(login)
+	if errors.Is(err, helix.ErrNotFound) {
+		return fmt.Sprintf("User %s does not exist.", displayName)
+	}
+	if err != nil {
+		return fmt.Sprintf("Could not get the user: %s", err.Error())
+	}
+
+	return fmt.Sprintf("%s created their account %s ago (%s).", user.DisplayName,
+		utils.FormatSince(user.CreatedAt, time.Now()), user.CreatedAt.Format("2006-01-02"))
+}
@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Handles !commands alias, args are the words after "alias".
+//
+// expected format: !commands alias add <alias> <commandname> | !commands alias remove <alias>
+func (b *TwitchBot) aliasCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	if len(args) == 0 {
+		return "Missing subcommand, use add or remove."
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			return "Missing alias or command name."
+		}
+
+		alias, err := b.parseCommandName(args[1])
+		if err != nil {
+			return err.Error()
+		}
+
+		commandName, err := b.parseCommandName(args[2])
+		if err != nil {
+			return err.Error()
+		}
+
+		// The alias must not shadow a command or another alias.
+		if _, err := b.Service.GetOneTwitchCommand(channel.Name, alias); err != sql.ErrNoRows {
+			if err != nil {
+				return err.Error()
+			}
+			return fmt.Sprintf("Command %s already exists.", b.displayCommand(alias))
+		}
+
+		// Aliases of aliases point to the actual command.
+		comm, err := b.Service.GetOneTwitchCommand(channel.Name, commandName)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Sprintf("Command %s does not exist.", b.displayCommand(commandName))
+			}
+			return err.Error()
+		}
+
+		err = b.Service.AddCommandAlias(database.CommandAlias{
+			Channel: channel.Name,
+			Alias:   alias,
+			Command: comm.Name,
+			Added:   time.Now(),
+		})
+		if err != nil {
+			if err == sql.ErrTxDone {
+				return fmt.Sprintf("Alias %s already exists.", b.displayCommand(alias))
+			}
+			return err.Error()
+		}
+
+		b.recordEvent(channel.Name, types.CommandAliasAdded, types.CommandAliasEvent{
+			Issuer:      message.User.Name,
+			Alias:       alias,
+			CommandName: comm.Name,
+		})
+
+		return fmt.Sprintf("Alias %s for %s has successfully been added.", b.displayCommand(alias), b.displayCommand(comm.Name))
+
+	case "remove":
+		if len(args) < 2 {
+			return "Missing alias."
+		}
+
+		alias, err := b.parseCommandName(args[1])
+		if err != nil {
+			return err.Error()
+		}
+
+		if err := b.Service.DeleteCommandAlias(channel.Name, alias); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Sprintf("Alias %s does not exist.", b.displayCommand(alias))
+			}
+			return err.Error()
+		}
+
+		b.recordEvent(channel.Name, types.CommandAliasRemoved, types.CommandAliasEvent{
+			Issuer: message.User.Name,
+			Alias:  alias,
+		})
+
+		return fmt.Sprintf("Alias %s has successfully been removed.", b.displayCommand(alias))
+
+	default:
+		return fmt.Sprintf("Invalid subcommand: %s", args[0])
+	}
+}
+
+// Lists the commands of a channel with their aliases, e.g. "!discord (!dc, !disc), !hug".
+func (b *TwitchBot) listCommands(channel *Channel) (string, error) {
+	comms, err := b.Service.GetAllTwitchCommands(channel.Name)
+	if err != nil {
+		return "", err
+	}
+
+	// Return custom message if no commands on database.
+	if len(comms) == 0 {
+		return "No commands on database yet.", nil
+	}
+
+	aliases, err := b.Service.GetAllCommandAliases(channel.Name)
+	if err != nil {
+		return "", err
+	}
+
+	aliasesByCommand := make(map[string][]string)
+	for _, alias := range aliases {
+		aliasesByCommand[alias.Command] = append(aliasesByCommand[alias.Command], b.displayCommand(alias.Alias))
+	}
+
+	sort.Slice(comms, func(i, j int) bool {
+		return comms[i].Name < comms[j].Name
+	})
+
+	cNames := []string{}
+
+	for _, comm := range comms {
+		name := b.displayCommand(comm.Name)
+
+		if commAliases := aliasesByCommand[comm.Name]; len(commAliases) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(commAliases, ", "))
+		}
+
+		cNames = append(cNames, name)
+	}
+
+	return strings.Join(cNames, ", "), nil
+}
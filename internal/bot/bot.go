@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -13,56 +12,141 @@ import (
 	"github.com/devusSs/twitch-kraken/internal/bot/types"
 	"github.com/devusSs/twitch-kraken/internal/config"
 	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/helix"
 	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/devusSs/twitch-kraken/internal/spotify"
 	"github.com/devusSs/twitch-kraken/internal/utils"
 	"github.com/gempir/go-twitch-irc/v4"
 )
 
 type TwitchBot struct {
-	Channel string
-	Owner   string
-	Editors []string
-	Client  *twitch.Client
-	Service database.Service
+	// Joined channels, keyed by their (lowercase) name.
+	Channels map[string]*Channel
+	Client   *twitch.Client
+	Service  database.Service
+	Helix    *helix.Client
+	Spotify  *spotify.Client
+	// Channel of the Spotify account's owner, the only channel which shows its tracks.
+	SpotifyChannel string
+	// Thank-you templates for USERNOTICE events, keyed by their event type.
+	Notices map[types.EventType]string
+	// Command prefixes, longest first.
+	Prefixes []string
+	// Cooldown in seconds for commands added without -cd=.
+	DefaultCooldown int
+	// Whether answers are sent as reply or mention (ResponseModeReply, ResponseModeMention), keyed by response type.
+	ResponseModes map[responseType]string
+	// Level needed to use !quote add.
+	QuoteAddLevel types.UserLevel
+
+	queue     *messageQueue
+	cooldowns *cooldownManager
+	timers    *timerScheduler
+	points    *pointsManager
+	// Nil if song requests are disabled.
+	songRequests *songRequestManager
+	// Nil if now playing announcements and the song history are disabled.
+	nowPlaying *nowPlayingPoller
+	// Thanked mystery gifts, their single gift subs are not thanked again.
+	mysteryGifts mysteryGifts
 }
 
 // Inits a new Twitch client and bot instance.
-func New(cfg *config.Config, svc database.Service) *TwitchBot {
+func New(cfg *config.Config, svc database.Service, helixClient *helix.Client, spotifyClient *spotify.Client) *TwitchBot {
 	bot := TwitchBot{}
 
 	client := twitch.NewClient(cfg.Twitch.BotLogin, fmt.Sprintf("oauth:%s", cfg.Twitch.BotPassword))
 
 	client.Capabilities = []string{twitch.TagsCapability, twitch.CommandsCapability, twitch.MembershipCapability}
 
-	bot.Channel = cfg.Twitch.JoinChannel
-	bot.Owner = cfg.Twitch.BotOwner
-	bot.Editors = cfg.Twitch.Editors
+	bot.Channels = make(map[string]*Channel)
+	for _, channel := range cfg.Twitch.Channels {
+		bot.Channels[channel.Name] = newChannel(channel)
+	}
+
 	bot.Client = client
 	bot.Service = svc
+	bot.Helix = helixClient
+	bot.Spotify = spotifyClient
+	bot.SpotifyChannel = cfg.SpotifyAuth.Channel
+	bot.queue = newMessageQueue(client, bot.isBotMod)
+	bot.timers = newTimerScheduler(&bot)
+	bot.points = newPointsManager(&bot, cfg)
+
+	if cfg.SongRequests.Enabled {
+		bot.songRequests = newSongRequestManager(&bot, cfg)
+	}
+
+	if cfg.NowPlaying.Enabled {
+		bot.nowPlaying = newNowPlayingPoller(&bot, cfg)
+	}
+
+	bot.Notices = map[types.EventType]string{
+		types.UserSub:         cfg.Notices.Sub,
+		types.UserResub:       cfg.Notices.Resub,
+		types.UserSubGift:     cfg.Notices.SubGift,
+		types.UserMysteryGift: cfg.Notices.MysteryGift,
+		types.UserGiftUpgrade: cfg.Notices.GiftUpgrade,
+		types.ChannelRaid:     cfg.Notices.Raid,
+	}
+
+	// Only store cooldowns on the database if they should survive a restart.
+	if cfg.Command.PersistCooldowns {
+		bot.cooldowns = newCooldownManager(svc)
+	} else {
+		bot.cooldowns = newCooldownManager(nil)
+	}
+
+	if err := bot.cooldowns.Load(); err != nil {
+		logging.WriteError(err)
+	}
+
+	if err := bot.loadOpenGiveaways(); err != nil {
+		logging.WriteError(err)
+	}
+
+	bot.Prefixes = sortPrefixes(cfg.Command.Prefixes)
+	bot.DefaultCooldown = cfg.Command.DefaultCooldown
+
+	bot.QuoteAddLevel = types.Moderator
+	if cfg.Quotes.SubsCanAdd {
+		bot.QuoteAddLevel = types.Subscriber
+	}
+
+	bot.ResponseModes = map[responseType]string{
+		ResponseCommand:    cfg.Responses.Commands,
+		ResponseGateKeeper: cfg.Responses.GateKeeper,
+	}
 
 	return &bot
 }
 
-// Join the specified channel and connect to Twitch's IRC server.
+// Join the specified channels and connect to Twitch's IRC server.
 func (b *TwitchBot) Connect() error {
-	b.Client.Join(b.Channel)
+	for name := range b.Channels {
+		b.Client.Join(name)
+	}
 	return b.Client.Connect()
 }
 
 // Default function to send a chat message on specified channel.
-func (b *TwitchBot) SendMessage(message string) {
-	b.Client.Say(b.Channel, message)
+//
+// The message is queued and sent as soon as Twitch's rate limits allow it.
+func (b *TwitchBot) SendMessage(channel, message string) {
+	b.queue.Enqueue(channel, message)
 }
 
 // Send a welcome message to chat so the users know the bot is online.
-func (b *TwitchBot) SendHelloMessage() {
-	b.SendMessage(fmt.Sprintf("@%s => Bot is up and running!", b.Owner))
-	logging.WriteSuccess("Sent hello message to chat")
+func (b *TwitchBot) SendHelloMessage(channel *Channel) {
+	b.SendMessage(channel.Name, fmt.Sprintf("@%s => Bot is up and running!", channel.Owner))
+	logging.WriteSuccess(fmt.Sprintf("Sent hello message to chat \"%s\"", channel.Name))
 }
 
-// Send a whisper message to the bot owner that an update is available.
+// Send a message to the owner of every channel that an update is available.
 func (b *TwitchBot) SendUpdateNotification(version string) {
-	b.SendMessage(fmt.Sprintf("@%s => New Bot version (%s) available!", b.Owner, version))
+	for _, channel := range b.Channels {
+		b.SendMessage(channel.Name, fmt.Sprintf("@%s => New Bot version (%s) available!", channel.Owner, version))
+	}
 	logging.WriteSuccess("Sent update message to chat")
 }
 
@@ -76,16 +160,39 @@ func (b *TwitchBot) AwaitCancel() {
 	fmt.Println("")
 }
 
-// Leave the channel and disconnect from Twitch server.
+// Leave the channels and disconnect from Twitch server.
 func (b *TwitchBot) Disconnect(wg *sync.WaitGroup) error {
-	b.Client.Depart(b.Channel)
+	for name := range b.Channels {
+		b.Client.Depart(name)
+
+		// Twitch does not send PARTs for the viewers once the bot left.
+		if err := b.Service.CloseAllViewerSessions(name, time.Now()); err != nil {
+			logging.WriteError(err)
+		}
+	}
+	b.timers.Stop()
+	b.points.Stop()
+	if b.songRequests != nil {
+		b.songRequests.Stop()
+	}
+	if b.nowPlaying != nil {
+		b.nowPlaying.Stop()
+	}
+	b.queue.Stop()
 	err := b.Client.Disconnect()
 	wg.Done()
 	return err
 }
 
 // General function to setup handlers for all Twitch / TMI events.
-func (b *TwitchBot) SetupHandleFuncs(g *gatekeeper.GateKeeper, newVersion string) {
+//
+// # NOTE: Every channel needs a Gatekeeper before calling this function.
+func (b *TwitchBot) SetupHandleFuncs(newVersion string) {
+	// Let the Gatekeepers adjust their filters to the current chat modes.
+	for _, channel := range b.Channels {
+		channel.GateKeeper.SetRoomStateProvider(channel.RoomState)
+	}
+
 	// When we connect to the Twitch chat.
 	b.Client.OnConnect(func() {
 		logging.WriteSuccess("Successfully connected to Twitch")
@@ -93,24 +200,50 @@ func (b *TwitchBot) SetupHandleFuncs(g *gatekeeper.GateKeeper, newVersion string
 
 	// Default channel message. Bot ignores whisper messages.
 	b.Client.OnPrivateMessage(func(message twitch.PrivateMessage) {
-		if err := b.AddUserDetailsOnMessage(message); err != nil {
+		channel, ok := b.GetChannel(message.Channel)
+		if !ok {
+			return
+		}
+
+		channel.setID(message.RoomID)
+		channel.addChatter(message.User.DisplayName)
+		b.timers.CountLine(channel.Name)
+		b.points.CountMessage(channel, message)
+
+		if err := b.AddUserDetailsOnMessage(channel, message); err != nil {
+			logging.WriteError(err)
+		}
+
+		_, err := b.Service.AddMessageEvent(database.MessageEvent{
+			Channel:   channel.Name,
+			MessageID: message.ID,
+			Issuer:    message.User.Name,
+			Content:   message.Message,
+			Sent:      message.Time,
+		})
+		if err != nil {
 			logging.WriteError(err)
 		}
 
 		// Checks a user's Twitch chat message for the specified filters.
 		//
 		// Will issue a purge, timeout (10 mins) or ban depending on result.
-		gkRes, gkReas, gkLog := g.FilterMessage(message)
+		gkRes, gkReas, gkLog := channel.GateKeeper.FilterMessage(message)
 		if gkRes != gatekeeper.NoneResult {
-			b.Client.Say(b.Channel, fmt.Sprintf("@%s => %s", message.User.DisplayName, gkReas))
+			// Mark the message as deleted by the bot before Twitch reports the deletion.
+			if err := b.RecordDeletedMessage(channel.Name, message.User.Name, message.ID, message.Message, true); err != nil {
+				logging.WriteError(err)
+			}
+
+			b.Respond(ResponseGateKeeper, message, string(gkReas))
 
 			switch gkRes {
 			case gatekeeper.IssuePurge:
-				b.PurgeUser(message.User.DisplayName, string(gkLog))
+				b.PurgeMessage(channel, message.ID)
 			case gatekeeper.IssueTimeout:
-				b.TimeoutUser(message.User.DisplayName, string(gkLog), 600)
+				b.TimeoutUser(channel, message.User.ID, string(gkLog), 600)
 			case gatekeeper.IssueBan:
-				b.BanUser(message.User.DisplayName, string(gkLog))
+				b.BanUser(channel, message.User.ID, string(gkLog))
 			default:
 				logging.WriteError(fmt.Sprintf("Got invalid GateKeeper result %d with reason %s", gkRes, gkReas))
 			}
@@ -118,26 +251,30 @@ func (b *TwitchBot) SetupHandleFuncs(g *gatekeeper.GateKeeper, newVersion string
 			return
 		}
 
-		_, err := b.Service.AddMessageEvent(database.MessageEvent{
-			Issuer:  message.User.Name,
-			Content: message.Message,
-			Sent:    message.Time,
-		})
-		if err != nil {
-			logging.WriteError(err)
+		// Giveaway keywords may look like commands (e.g. "!join"), so check them first.
+		if b.handleGiveawayEntry(channel, message) {
+			return
 		}
 
-		// Check for leading "!" in case message might be a bot command.
-		if strings.Index(message.Message, "!") == 0 {
-			commReturn := b.commandHandler(message)
-			b.Client.Say(b.Channel, fmt.Sprintf("@%s => %s", message.User.DisplayName, commReturn))
+		// Commands may be sent as reply to another message, Twitch prefixes those with "@user ".
+		commMessage := stripReplyMention(message)
+
+		// Check for a leading prefix in case message might be a bot command.
+		if b.isCommand(commMessage.Message) {
+			commReturn := b.commandHandler(channel, commMessage)
+			b.Respond(ResponseCommand, message, commReturn)
 			return
 		}
 	})
 
 	// Ban or timeout events
 	b.Client.OnClearChatMessage(func(message twitch.ClearChatMessage) {
-		if err := b.AddUserDetailsOnBan(message); err != nil {
+		channel, ok := b.GetChannel(message.Channel)
+		if !ok {
+			return
+		}
+
+		if err := b.AddUserDetailsOnBan(channel, message); err != nil {
 			logging.WriteError(err)
 		}
 
@@ -151,6 +288,7 @@ func (b *TwitchBot) SetupHandleFuncs(g *gatekeeper.GateKeeper, newVersion string
 		}
 
 		var event database.AuthEvent
+		event.Channel = channel.Name
 		event.Type = types.UserBan
 		// If ban duration > 0 it's a timeout event, else it's a ban event.
 		if message.BanDuration > 0 {
@@ -165,54 +303,100 @@ func (b *TwitchBot) SetupHandleFuncs(g *gatekeeper.GateKeeper, newVersion string
 		}
 	})
 
-	// TODO: implement later
-	/*
-		// Message was purged event
-		b.Client.OnClearMessage(func(message twitch.ClearMessage) {})
+	// Events like gaining a sub, resub, gift subs or raids
+	b.Client.OnUserNoticeMessage(func(message twitch.UserNoticeMessage) {
+		if err := b.HandleUserNotice(message); err != nil {
+			logging.WriteError(err)
+		}
+	})
 
-			// Events like submode, follower-only, etc.
-			b.Client.OnRoomStateMessage(func(message twitch.RoomStateMessage) {})
+	// Events like submode, follower-only, slow mode, etc.
+	b.Client.OnRoomStateMessage(func(message twitch.RoomStateMessage) {
+		if err := b.HandleRoomState(message); err != nil {
+			logging.WriteError(err)
+		}
+	})
 
-			// ??
-			b.Client.OnGlobalUserStateMessage(func(message twitch.GlobalUserStateMessage) {})
+	// Message was purged event
+	b.Client.OnClearMessage(func(message twitch.ClearMessage) {
+		if err := b.HandleClearMessage(message); err != nil {
+			logging.WriteError(err)
+		}
+	})
 
-			// Events like hosting or raiding another channel
-			b.Client.OnNoticeMessage(func(message twitch.NoticeMessage) {})
+	// Bot's own state in a channel, tells us if the bot is mod there (rate limits).
+	b.Client.OnUserStateMessage(func(message twitch.UserStateMessage) {
+		channel, ok := b.GetChannel(message.Channel)
+		if !ok {
+			return
+		}
 
-			// Events like gaining a sub, resub or raids
-			b.Client.OnUserNoticeMessage(func(message twitch.UserNoticeMessage) {})
+		channel.botIsMod.Store(message.User.Badges["moderator"] == 1 || message.User.Badges["broadcaster"] == 1)
+	})
 
-			// ??
-			b.Client.OnUserStateMessage(func(message twitch.UserStateMessage) {})
+	// TODO: implement later
+	/*
+		// ??
+		b.Client.OnGlobalUserStateMessage(func(message twitch.GlobalUserStateMessage) {})
+
+		// Events like hosting or raiding another channel
+		b.Client.OnNoticeMessage(func(message twitch.NoticeMessage) {})
 	*/
 
 	// User joins channel event
 	b.Client.OnUserJoinMessage(func(message twitch.UserJoinMessage) {
-		if err := b.AddUserOnConnect(message); err != nil {
+		channel, ok := b.GetChannel(message.Channel)
+		if !ok {
+			return
+		}
+
+		if err := b.AddUserOnConnect(channel, message); err != nil {
 			logging.WriteError(err)
 		}
 	})
 
 	// User leaves channel event
 	b.Client.OnUserPartMessage(func(message twitch.UserPartMessage) {
-		if err := b.EditUserOnDisconnect(message); err != nil {
+		channel, ok := b.GetChannel(message.Channel)
+		if !ok {
+			return
+		}
+
+		if err := b.EditUserOnDisconnect(channel, message); err != nil {
 			logging.WriteError(err)
 		}
 	})
 
 	// Reconnect event
 	b.Client.OnSelfJoinMessage(func(message twitch.UserJoinMessage) {
-		logging.WriteSuccess(fmt.Sprintf("Successfully joined channel \"%s\"", b.Channel))
-		b.SendHelloMessage()
+		channel, ok := b.GetChannel(message.Channel)
+		if !ok {
+			return
+		}
+
+		// Sessions still open from a crash or lost connection can not be paired with a PART anymore.
+		if err := b.Service.CloseStaleViewerSessions(channel.Name); err != nil {
+			logging.WriteError(err)
+		}
+
+		logging.WriteSuccess(fmt.Sprintf("Successfully joined channel \"%s\"", channel.Name))
+		b.SendHelloMessage(channel)
 		if newVersion != "" {
-			b.SendUpdateNotification(newVersion)
+			b.SendMessage(channel.Name, fmt.Sprintf("@%s => New Bot version (%s) available!", channel.Owner, newVersion))
 		}
 	})
 
 	// Disconnect event
 	b.Client.OnSelfPartMessage(func(message twitch.UserPartMessage) {
-		logging.WriteError("Left channel, attempting to rejoin...")
-		b.Client.Join(b.Channel)
+		logging.WriteError(fmt.Sprintf("Left channel \"%s\", attempting to rejoin...", message.Channel))
+
+		if channel, ok := b.GetChannel(message.Channel); ok {
+			if err := b.Service.CloseAllViewerSessions(channel.Name, time.Now()); err != nil {
+				logging.WriteError(err)
+			}
+		}
+
+		b.Client.Join(message.Channel)
 	})
 
 	logging.WriteSuccess("Setup handle functions for Twitch events")
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/gatekeeper"
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/config"
+	"github.com/devusSs/twitch-kraken/internal/database"
+)
+
+// Twitch channel the bot joined. Commands, Gatekeeper settings and users are scoped per channel.
+type Channel struct {
+	Name       string
+	Owner      string
+	Editors    []string
+	Currency   string // name of the channel's loyalty points
+	GateKeeper *gatekeeper.GateKeeper
+
+	// Twitch user ID of the channel (room-id tag), needed for Helix requests.
+	id        string
+	roomState types.RoomState
+	// Display names of users who chatted recently and when they did, used for ${randomuser}.
+	chatters map[string]time.Time
+	// When expired chatters were removed last.
+	lastChatterPrune time.Time
+	// Giveaway which accepts entries right now, nil if there is none.
+	giveaway *database.Giveaway
+	// Usernames which entered the open giveaway.
+	giveawayEntrants map[string]bool
+	// Guards id, roomState, chatters and the giveaway.
+	mu sync.RWMutex
+
+	// Whether the bot is mod or broadcaster in this channel, updated via USERSTATE.
+	botIsMod atomic.Bool
+}
+
+// Inits a new channel from the config. The Gatekeeper needs to be set separately.
+func newChannel(cfg config.Channel) *Channel {
+	c := Channel{}
+
+	c.Name = cfg.Name
+	c.Owner = cfg.Owner
+	c.Editors = cfg.Editors
+	c.Currency = cfg.Currency
+	c.roomState = types.DefaultRoomState()
+	c.chatters = make(map[string]time.Time)
+
+	return &c
+}
+
+// Returns the Twitch user ID of the channel, empty until the first message of the channel was received.
+func (c *Channel) ID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.id
+}
+
+// Stores the Twitch user ID of the channel (room-id tag).
+func (c *Channel) setID(id string) {
+	if id == "" {
+		return
+	}
+	c.mu.Lock()
+	c.id = id
+	c.mu.Unlock()
+}
+
+// How long a user counts as recent chatter after their last message.
+const chatterWindow = 10 * time.Minute
+
+// Remembers that the user chatted just now and forgets users who left the chatterWindow.
+func (c *Channel) addChatter(displayName string) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.chatters[displayName] = now
+
+	// Pruning walks the whole map, once per window is enough.
+	if now.Sub(c.lastChatterPrune) < chatterWindow {
+		return
+	}
+
+	for name, seen := range c.chatters {
+		if now.Sub(seen) > chatterWindow {
+			delete(c.chatters, name)
+		}
+	}
+
+	c.lastChatterPrune = now
+}
+
+// Returns a random user who chatted within the chatterWindow, false if nobody did.
+func (c *Channel) RandomChatter() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	recent := []string{}
+
+	for name, seen := range c.chatters {
+		if time.Since(seen) > chatterWindow {
+			delete(c.chatters, name)
+			continue
+		}
+		recent = append(recent, name)
+	}
+
+	if len(recent) == 0 {
+		return "", false
+	}
+
+	return recent[rand.Intn(len(recent))], true
+}
+
+// Returns the giveaway which accepts entries right now.
+func (c *Channel) OpenGiveaway() (database.Giveaway, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.giveaway == nil {
+		return database.Giveaway{}, false
+	}
+	return *c.giveaway, true
+}
+
+// Sets the giveaway which accepts entries, nil if entries are closed.
+func (c *Channel) setOpenGiveaway(giveaway *database.Giveaway) {
+	c.mu.Lock()
+	c.giveaway = giveaway
+	c.giveawayEntrants = make(map[string]bool)
+	c.mu.Unlock()
+}
+
+// Remembers that the user entered the open giveaway, returns false if they already did.
+func (c *Channel) markGiveawayEntrant(username string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.giveawayEntrants[username] {
+		return false
+	}
+	c.giveawayEntrants[username] = true
+	return true
+}
+
+// Forgets an entrant again, e.g. if the entry could not be stored.
+func (c *Channel) unmarkGiveawayEntrant(username string) {
+	c.mu.Lock()
+	delete(c.giveawayEntrants, username)
+	c.mu.Unlock()
+}
+
+// Returns true if the bot is mod or broadcaster in the specified channel.
+func (b *TwitchBot) isBotMod(name string) bool {
+	c, ok := b.GetChannel(name)
+	if !ok {
+		return false
+	}
+	return c.botIsMod.Load()
+}
+
+// Returns the joined channel with the specified name (with or without leading "#").
+func (b *TwitchBot) GetChannel(name string) (*Channel, bool) {
+	c, ok := b.Channels[strings.ToLower(strings.TrimPrefix(name, "#"))]
+	return c, ok
+}
@@ -0,0 +1,207 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/helix"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+const (
+	// Longest title Twitch accepts.
+	maxTitleLength = 140
+	// How many categories are compared when looking up a game.
+	categorySearchLimit = 20
+)
+
+// Returns why the channel information can not be changed, empty if it can.
+//
+// Twitch only accepts changes with a token of the broadcaster, which is the token the bot's Helix client uses.
+func (b *TwitchBot) channelInfoEditable(channel *Channel) string {
+	tokenUserID, err := b.Helix.TokenUserID()
+	if err != nil {
+		return fmt.Sprintf("Could not get the Twitch account of the bot's token: %s", err.Error())
+	}
+
+	if channel.ID() != "" && channel.ID() == tokenUserID {
+		return ""
+	}
+
+	owner := ""
+	for _, c := range b.Channels {
+		if c.ID() == tokenUserID {
+			owner = c.Name
+			break
+		}
+	}
+
+	// The owner's channel may not have sent a message yet.
+	if owner == "" {
+		if users, err := b.Helix.GetUsers([]string{tokenUserID}, nil); err == nil && len(users) > 0 {
+			owner = users[0].Login
+		}
+	}
+
+	if owner == "" {
+		return "Changing the channel information is not available in this channel."
+	}
+
+	return fmt.Sprintf("Changing the channel information is only available in %s.", owner)
+}
+
+// Handles the built-in !title command, shows the stream title.
+//
+// expected format: !title
+func (b *TwitchBot) titleCommand(channel *Channel) string {
+	info, err := b.Helix.GetChannelInformation(channel.ID())
+	if err != nil {
+		return fmt.Sprintf("Could not get the channel information: %s", err.Error())
+	}
+
+	return fmt.Sprintf("Title: %s", info.Title)
+}
+
+// Handles the built-in !settitle command, mods change the stream title.
+//
+// expected format: !settitle <title>
+func (b *TwitchBot) setTitleCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	if answer := b.channelInfoEditable(channel); answer != "" {
+		return answer
+	}
+
+	title := strings.Join(args, " ")
+	if title == "" {
+		return "Please specify a title."
+	}
+
+	if len([]rune(title)) > maxTitleLength {
+		return fmt.Sprintf("The title may be %d characters at most.", maxTitleLength)
+	}
+
+	info, err := b.Helix.GetChannelInformation(channel.ID())
+	if err != nil {
+		return fmt.Sprintf("Could not get the channel information: %s", err.Error())
+	}
+
+	if err := b.Helix.ModifyChannelInformation(channel.ID(), helix.ModifyChannelParams{Title: title}); err != nil {
+		return fmt.Sprintf("Could not change the title: %s", err.Error())
+	}
+
+	b.recordEvent(channel.Name, types.ChannelTitleChanged, types.ChannelInfoEvent{
+		Issuer: message.User.Name,
+		Old:    info.Title,
+		New:    title,
+	})
+
+	return fmt.Sprintf("Title changed to: %s", title)
+}
+
+// Handles the built-in !game command, shows the stream category.
+//
+// expected format: !game
+func (b *TwitchBot) gameCommand(channel *Channel) string {
+	info, err := b.Helix.GetChannelInformation(channel.ID())
+	if err != nil {
+		return fmt.Sprintf("Could not get the channel information: %s", err.Error())
+	}
+
+	if info.GameName == "" {
+		return "No game is set."
+	}
+
+	return fmt.Sprintf("Game: %s", info.GameName)
+}
+
+// Handles the built-in !setgame command, mods change the stream category.
+//
+// The game is looked up via Twitch's category search, so it does not need to be spelled exactly.
+//
+// expected format: !setgame <game>
+func (b *TwitchBot) setGameCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	if answer := b.channelInfoEditable(channel); answer != "" {
+		return answer
+	}
+
+	query := strings.Join(args, " ")
+	if query == "" {
+		return "Please specify a game."
+	}
+
+	categories, err := b.Helix.SearchCategories(query, categorySearchLimit)
+	if err != nil {
+		return fmt.Sprintf("Could not search for the game: %s", err.Error())
+	}
+
+	category, err := bestCategory(query, categories)
+	if errors.Is(err, helix.ErrNotFound) {
+		return fmt.Sprintf("Could not find a game matching %s.", query)
+	}
+
+	info, err := b.Helix.GetChannelInformation(channel.ID())
+	if err != nil {
+		return fmt.Sprintf("Could not get the channel information: %s", err.Error())
+	}
+
+	if err := b.Helix.ModifyChannelInformation(channel.ID(), helix.ModifyChannelParams{GameID: category.ID}); err != nil {
+		return fmt.Sprintf("Could not change the game: %s", err.Error())
+	}
+
+	b.recordEvent(channel.Name, types.ChannelGameChanged, types.ChannelInfoEvent{
+		Issuer: message.User.Name,
+		Old:    info.GameName,
+		New:    category.Name,
+		GameID: category.ID,
+	})
+
+	return fmt.Sprintf("Game changed to: %s", category.Name)
+}
+
+// Picks the category matching the query best, or ErrNotFound if there are none.
+//
+// Prefers an exact match, then a name starting with the query, then one containing it,
+// ignoring case, spaces and punctuation. Falls back to the most relevant search result.
+func bestCategory(query string, categories []helix.Category) (helix.Category, error) {
+	if len(categories) == 0 {
+		return helix.Category{}, helix.ErrNotFound
+	}
+
+	want := normalizeName(query)
+
+	matches := []func(name string) bool{
+		func(name string) bool { return name == want },
+		func(name string) bool { return strings.HasPrefix(name, want) },
+		func(name string) bool { return strings.Contains(name, want) },
+	}
+
+	for _, match := range matches {
+		for _, category := range categories {
+			if match(normalizeName(category.Name)) {
+				return category, nil
+			}
+		}
+	}
+
+	return categories[0], nil
+}
+
+// Lowercases the name and strips everything but letters and digits.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+)
+
+// Options of !commands add / edit, nil if not specified in the message.
+type commandFlags struct {
+	userlevel    *types.UserLevel
+	cooldown     *int
+	userCooldown *int
+	modBypass    *bool
+	cost         *int
+}
+
+// Splits the words of a !commands add / edit message into flags and the command output.
+//
+// Supported flags: -ul=<userlevel>, -cd=<cooldown>, -ucd=<per user cooldown>, -modbypass=<true|false>, -cost=<points>
+func parseCommandFlags(words []string) (commandFlags, []string, error) {
+	flags := commandFlags{}
+	output := []string{}
+
+	for _, word := range words {
+		switch {
+		case strings.HasPrefix(word, "-ul="):
+			value := strings.TrimPrefix(word, "-ul=")
+			level, err := types.ParseUserLevel(value)
+			if err != nil {
+				return flags, nil, fmt.Errorf("Invalid userlevel specified: %s", value)
+			}
+			flags.userlevel = &level
+
+		case strings.HasPrefix(word, "-cd="):
+			cooldown, err := parseCooldown(strings.TrimPrefix(word, "-cd="))
+			if err != nil {
+				return flags, nil, err
+			}
+			flags.cooldown = &cooldown
+
+		case strings.HasPrefix(word, "-ucd="):
+			cooldown, err := parseCooldown(strings.TrimPrefix(word, "-ucd="))
+			if err != nil {
+				return flags, nil, err
+			}
+			flags.userCooldown = &cooldown
+
+		case strings.HasPrefix(word, "-modbypass="):
+			value := strings.TrimPrefix(word, "-modbypass=")
+			bypass, err := strconv.ParseBool(value)
+			if err != nil {
+				return flags, nil, fmt.Errorf("Invalid modbypass specified: %s", value)
+			}
+			flags.modBypass = &bypass
+
+		case strings.HasPrefix(word, "-cost="):
+			value := strings.TrimPrefix(word, "-cost=")
+			cost, err := strconv.Atoi(value)
+			if err != nil || cost < 0 {
+				return flags, nil, fmt.Errorf("Invalid cost specified: %s", value)
+			}
+			flags.cost = &cost
+
+		default:
+			output = append(output, word)
+		}
+	}
+
+	return flags, output, nil
+}
+
+// Converts a cooldown flag value (seconds) to an int.
+func parseCooldown(value string) (int, error) {
+	cooldown, err := strconv.Atoi(value)
+	if err != nil || cooldown < 0 {
+		return 0, fmt.Errorf("Invalid cooldown specified: %s", value)
+	}
+	return cooldown, nil
+}
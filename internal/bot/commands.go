@@ -3,10 +3,10 @@ package bot
 import (
 	"database/sql"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/devusSs/twitch-kraken/internal/bot/template"
 	"github.com/devusSs/twitch-kraken/internal/bot/types"
 	"github.com/devusSs/twitch-kraken/internal/database"
 	"github.com/devusSs/twitch-kraken/internal/logging"
@@ -14,15 +14,15 @@ import (
 	"github.com/gempir/go-twitch-irc/v4"
 )
 
-// Handles any message which starts with a "!".
-func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
+// Handles any message which starts with one of the configured prefixes.
+//
+// If the command was sent as reply, message.Reply holds the message it replies to.
+func (b *TwitchBot) commandHandler(channel *Channel, message twitch.PrivateMessage) string {
 	messageSplit := strings.Split(message.Message, " ")
-	commName := messageSplit[0]
+	commName, _ := b.trimPrefix(messageSplit[0])
 
 	switch commName {
-	case "!commands":
-		var err error
-
+	case "commands":
 		// Handle subcommands like add, edit, delete here.
 		subCommand := ""
 
@@ -32,9 +32,9 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 		}
 
 		switch subCommand {
-		// expected format: !commands add <commandname> <output> (-ul=<userlevel>) (-cd=<cooldown>)
+		// expected format: !commands add <commandname> <output> (-ul=<userlevel>) (-cd=<cooldown>) (-ucd=<user cooldown>) (-modbypass=<true|false>) (-cost=<points>)
 		case "add":
-			if !b.IsUserModOrOwner(message) {
+			if !channel.HasUserLevel(message, types.Moderator) {
 				return "You are not allowed to use that command."
 			}
 
@@ -46,92 +46,66 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 				}
 			}
 
-			commandName := messageSplit[0]
-			userlevel := "anyone"
-			cooldown := 0
-
-			uLevelInMsg := false
-			cdInMsg := false
-
-			// Check the slice for userlevel and cooldown specification.
-			for _, str := range messageSplit {
-				if strings.Contains(str, "-ul=") {
-					userlevel = strings.Split(str, "-ul=")[1]
-					uLevelInMsg = true
-				}
+			if len(messageSplit) == 0 {
+				return "Missing command name."
+			}
 
-				if strings.Contains(str, "-cd=") {
-					cooldown, err = strconv.Atoi(strings.Split(str, "-cd=")[1])
-					if err != nil {
-						return err.Error()
-					}
-					cdInMsg = true
-				}
+			commandName, err := b.parseCommandName(messageSplit[0])
+			if err != nil {
+				return err.Error()
 			}
 
-			// Remove command name, uselevel and cooldown from slice.
-			messageSplit, err = utils.RemoveStringFromSlice(messageSplit, 0)
+			flags, output, err := parseCommandFlags(messageSplit[1:])
 			if err != nil {
 				return err.Error()
 			}
 
-			if uLevelInMsg {
-				for i, str := range messageSplit {
-					if strings.Contains(str, "-ul=") {
-						messageSplit, err = utils.RemoveStringFromSlice(messageSplit, i)
-						if err != nil {
-							return err.Error()
-						}
-					}
-				}
+			// Add command to database.
+			comm := database.TwitchCommand{}
+			comm.Channel = channel.Name
+			comm.Name = commandName
+			comm.Output = strings.Join(output, " ")
+			comm.Userlevel = types.Anyone
+			comm.Cooldown = b.DefaultCooldown
+
+			if flags.userlevel != nil {
+				comm.Userlevel = *flags.userlevel
 			}
 
-			if cdInMsg {
-				for i, str := range messageSplit {
-					if strings.Contains(str, "-cd=") {
-						messageSplit, err = utils.RemoveStringFromSlice(messageSplit, i)
-						if err != nil {
-							return err.Error()
-						}
-					}
-				}
+			if flags.cooldown != nil {
+				comm.Cooldown = *flags.cooldown
 			}
 
-			// Double check for valid userlevel and cooldown.
-			userlevel = strings.ToLower(userlevel)
+			if flags.userCooldown != nil {
+				comm.UserCooldown = *flags.userCooldown
+			}
 
-			if userlevel != "anyone" && userlevel != "moderator" && userlevel != "owner" {
-				return fmt.Sprintf("Invalid userlevel specified: %s", userlevel)
+			if flags.modBypass != nil {
+				comm.ModBypass = *flags.modBypass
 			}
 
-			if cooldown < 0 {
-				return fmt.Sprintf("Invalid cooldown specified: %d", cooldown)
+			if flags.cost != nil {
+				comm.Cost = *flags.cost
 			}
 
-			// Add command to database.
-			comm := database.TwitchCommand{}
-			comm.Name = commandName
-			comm.Output = strings.Join(messageSplit, " ")
+			if comm.Output == "" {
+				return "Missing command output."
+			}
 
-			// Convert userlevel to corresponding enum value.
-			switch userlevel {
-			case "anyone":
-				comm.Userlevel = types.Anyone
-			case "moderator":
-				comm.Userlevel = types.Moderator
-			case "owner":
-				comm.Userlevel = types.Owner
-			default:
-				return fmt.Sprintf("Invalid userlevel specified: %s", userlevel)
+			if err := template.Validate(comm.Output); err != nil {
+				return fmt.Sprintf("Invalid command output: %s", err.Error())
 			}
 
-			comm.Cooldown = cooldown
+			// Commands must not shadow an alias.
+			if existing, err := b.Service.GetOneTwitchCommand(channel.Name, comm.Name); err == nil && existing.Name != comm.Name {
+				return fmt.Sprintf("%s is already an alias of %s.", b.displayCommand(comm.Name), b.displayCommand(existing.Name))
+			}
 
 			comm.Added = time.Now()
 
 			if err := b.Service.AddTwitchCommand(comm); err != nil {
 				if err == sql.ErrTxDone {
-					return fmt.Sprintf("Command %s already exists.", comm.Name)
+					return fmt.Sprintf("Command %s already exists.", b.displayCommand(comm.Name))
 				}
 				return err.Error()
 			}
@@ -146,6 +120,7 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 				logging.WriteError(err)
 			}
 
+			event.Channel = channel.Name
 			event.Type = types.CommandAdded
 			event.Data = innerData
 			event.Timestamp = time.Now()
@@ -155,11 +130,11 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 				logging.WriteError(err)
 			}
 
-			return fmt.Sprintf("Command %s has successfully been added.", comm.Name)
+			return fmt.Sprintf("Command %s has successfully been added.", b.displayCommand(comm.Name))
 
-		// expected format: !commands edit <commandname> <output> (-ul=<userlevel>) (-cd=<cooldown>)
+		// expected format: !commands edit <commandname> (<output>) (-ul=<userlevel>) (-cd=<cooldown>) (-ucd=<user cooldown>) (-modbypass=<true|false>) (-cost=<points>)
 		case "edit":
-			if !b.IsUserModOrOwner(message) {
+			if !channel.HasUserLevel(message, types.Moderator) {
 				return "You are not allowed to use that command."
 			}
 
@@ -171,102 +146,57 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 				}
 			}
 
-			commandName := messageSplit[0]
-			userlevel := ""
-			cooldown := 0
-
-			uLevelInMsg := false
-			cdInMsg := false
-
-			// Check the slice for userlevel and cooldown specification.
-			for _, str := range messageSplit {
-				if strings.Contains(str, "-ul=") {
-					userlevel = strings.Split(str, "-ul=")[1]
-					uLevelInMsg = true
-				}
-
-				if strings.Contains(str, "-cd=") {
-					cooldown, err = strconv.Atoi(strings.Split(str, "-cd=")[1])
-					if err != nil {
-						return err.Error()
-					}
-					cdInMsg = true
-				}
+			if len(messageSplit) == 0 {
+				return "Missing command name."
 			}
 
-			// Remove command name, userlevel and cooldown from slice.
-			messageSplit, err = utils.RemoveStringFromSlice(messageSplit, 0)
+			commandName, err := b.parseCommandName(messageSplit[0])
 			if err != nil {
 				return err.Error()
 			}
 
-			if uLevelInMsg {
-				for i, str := range messageSplit {
-					if strings.Contains(str, "-ul=") {
-						messageSplit, err = utils.RemoveStringFromSlice(messageSplit, i)
-						if err != nil {
-							return err.Error()
-						}
-					}
-				}
-			}
-
-			if cdInMsg {
-				for i, str := range messageSplit {
-					if strings.Contains(str, "-cd=") {
-						messageSplit, err = utils.RemoveStringFromSlice(messageSplit, i)
-						if err != nil {
-							return err.Error()
-						}
-					}
-				}
+			flags, output, err := parseCommandFlags(messageSplit[1:])
+			if err != nil {
+				return err.Error()
 			}
 
-			// Grab old userlevel and cooldown if none were specified
-			oldCmd, err := b.Service.GetOneTwitchCommand(commandName)
+			// Keep old values for everything which was not specified.
+			newCmd, err := b.Service.GetOneTwitchCommand(channel.Name, commandName)
 			if err != nil {
 				if err == sql.ErrNoRows {
-					return fmt.Sprintf("Command %s does not exist.", commandName)
+					return fmt.Sprintf("Command %s does not exist.", b.displayCommand(commandName))
 				}
 				return err.Error()
 			}
 
-			if !uLevelInMsg {
-				userlevel = oldCmd.Userlevel.String()
-			}
+			if len(output) > 0 {
+				newCmd.Output = strings.Join(output, " ")
 
-			if !cdInMsg {
-				cooldown = oldCmd.Cooldown
+				if err := template.Validate(newCmd.Output); err != nil {
+					return fmt.Sprintf("Invalid command output: %s", err.Error())
+				}
 			}
 
-			// Double check for valid userlevel and cooldown.
-			userlevel = strings.ToLower(userlevel)
+			if flags.userlevel != nil {
+				newCmd.Userlevel = *flags.userlevel
+			}
 
-			if userlevel != "anyone" && userlevel != "moderator" && userlevel != "owner" {
-				return fmt.Sprintf("Invalid userlevel specified: %s", userlevel)
+			if flags.cooldown != nil {
+				newCmd.Cooldown = *flags.cooldown
 			}
 
-			if cooldown < 0 {
-				return fmt.Sprintf("Invalid cooldown specified: %d", cooldown)
+			if flags.userCooldown != nil {
+				newCmd.UserCooldown = *flags.userCooldown
 			}
 
-			newCmd := database.TwitchCommand{}
+			if flags.modBypass != nil {
+				newCmd.ModBypass = *flags.modBypass
+			}
 
-			// Convert userlevel to corresponding enum value.
-			switch userlevel {
-			case "anyone":
-				newCmd.Userlevel = types.Anyone
-			case "moderator":
-				newCmd.Userlevel = types.Moderator
-			case "owner":
-				newCmd.Userlevel = types.Owner
-			default:
-				return fmt.Sprintf("Invalid userlevel specified: %s", userlevel)
+			if flags.cost != nil {
+				newCmd.Cost = *flags.cost
 			}
 
-			newCmd.Name = commandName
-			newCmd.Output = strings.Join(messageSplit, " ")
-			newCmd.Cooldown = cooldown
 			newCmd.Edited.Time = time.Now()
 
 			cmdReturn, err := b.Service.UpdateTwitchCommand(newCmd)
@@ -284,6 +214,7 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 				logging.WriteError(err)
 			}
 
+			event.Channel = channel.Name
 			event.Type = types.CommandEdited
 			event.Data = innerData
 			event.Timestamp = time.Now()
@@ -293,31 +224,48 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 				logging.WriteError(err)
 			}
 
-			return fmt.Sprintf("Command %s has successfully been updated.", cmdReturn.Name)
+			return fmt.Sprintf("Command %s has successfully been updated.", b.displayCommand(cmdReturn.Name))
 
 		// expected format: !commands delete <commandname>
 		case "delete":
-			if !b.IsUserModOrOwner(message) {
+			if !channel.HasUserLevel(message, types.Moderator) {
 				return "You are not allowed to use that command."
 			}
 
-			if err := b.Service.DeleteTwitchCommand(messageSplit[len(messageSplit)-1]); err != nil {
-				if err == sql.ErrNoRows {
-					return fmt.Sprintf("Command %s does not exist.", messageSplit[len(messageSplit)-1])
-				}
+			if len(messageSplit) < 3 {
+				return "Missing command name."
+			}
+
+			commandName, err := b.parseCommandName(messageSplit[len(messageSplit)-1])
+			if err != nil {
 				return err.Error()
 			}
 
+			if err := b.Service.DeleteTwitchCommand(channel.Name, commandName); err != nil {
+				if err != sql.ErrNoRows {
+					return err.Error()
+				}
+
+				// Aliases are removed via !commands alias remove, so nobody deletes a command by accident.
+				if comm, err := b.Service.GetOneTwitchCommand(channel.Name, commandName); err == nil {
+					return fmt.Sprintf("%s is an alias of %s, use \"%s alias remove %s\" to remove it.",
+						b.displayCommand(commandName), b.displayCommand(comm.Name), b.displayCommand("commands"), commandName)
+				}
+
+				return fmt.Sprintf("Command %s does not exist.", b.displayCommand(commandName))
+			}
+
 			var event database.AuthEvent
 
 			innerData, err := utils.MarshalStruct(types.CommandEvent{
 				Issuer:      message.User.Name,
-				CommandName: messageSplit[len(messageSplit)-1],
+				CommandName: commandName,
 			})
 			if err != nil {
 				logging.WriteError(err)
 			}
 
+			event.Channel = channel.Name
 			event.Type = types.CommandDeleted
 			event.Data = innerData
 			event.Timestamp = time.Now()
@@ -327,60 +275,167 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 				logging.WriteError(err)
 			}
 
-			return fmt.Sprintf("Command %s has successfully been deleted.", messageSplit[len(messageSplit)-1])
+			return fmt.Sprintf("Command %s has successfully been deleted.", b.displayCommand(commandName))
+
+		// expected format: !commands alias add <alias> <commandname> | !commands alias remove <alias>
+		case "alias":
+			return b.aliasCommand(channel, message, messageSplit[2:])
 
 		// expected format: !commands
-		// Return a list of all available commands if no subcommand was specified.
+		// Return a list of all available commands (and their aliases) if no subcommand was specified.
 		case "":
-			comms, err := b.Service.GetAllTwitchCommands()
+			list, err := b.listCommands(channel)
 			if err != nil {
 				return err.Error()
 			}
 
-			// Return custom message if no commands on database.
-			if len(comms) == 0 {
-				return "No commands on database yet."
-			}
-
-			cNames := []string{}
-
-			for _, comm := range comms {
-				cNames = append(cNames, comm.Name)
-			}
-
-			return strings.Join(cNames, ", ")
+			return list
 
 		// No matching subcommand was found.
 		default:
 			return fmt.Sprintf("Invalid subcommand: %s", subCommand)
 		}
 
-	// TODO: implement more built-in commands like title, setttitle etc.
+	// expected format: !counter <name> | !counter (set|add|remove|reset) <name> (<value>)
+	case "counter":
+		return b.counterCommand(channel, message, messageSplit[1:])
+
+	// expected format: !timers (add|edit|delete|list) ...
+	case "timers":
+		return b.timersCommand(channel, message, messageSplit[1:])
+
+	// expected format: !quote (<number>) | !quote (add|search|delete) ...
+	case "quote":
+		return b.quoteCommand(channel, message, messageSplit[1:])
+
+	// expected format: !giveaway (start|close|draw|redraw) ...
+	case "giveaway":
+		return b.giveawayCommand(channel, message, messageSplit[1:])
+
+	// expected format: !watchtime (<user>)
+	case "watchtime":
+		return b.watchTimeCommand(channel, message, messageSplit[1:])
+
+	// expected format: !points (<user>)
+	case "points":
+		return b.pointsCommand(channel, message, messageSplit[1:])
+
+	// expected format: !give <user> <amount>
+	case "give":
+		return b.giveCommand(channel, message, messageSplit[1:])
+
+	// expected format: !top
+	case "top":
+		return b.topCommand(channel)
+
+	// expected format: !addpoints <user> <amount>
+	case "addpoints":
+		return b.addPointsCommand(channel, message, messageSplit[1:])
+
+	// expected format: !song
+	case "song":
+		return b.songCommand(channel)
+
+	// expected format: !lastsong
+	case "lastsong":
+		return b.lastSongCommand(channel)
+
+	// expected format: !sr <spotify link | search>
+	case "sr":
+		return b.songRequestCommand(channel, message, messageSplit[1:])
+
+	// expected format: !skip
+	case "skip":
+		return b.skipCommand(channel, message)
+
+	// expected format: !srclear
+	case "srclear":
+		return b.clearSongRequestsCommand(channel, message)
+
+	// expected format: !nowplaying (on | off)
+	case "nowplaying":
+		return b.nowPlayingCommand(channel, message, messageSplit[1:])
+
+	// expected format: !songat <h:mm | h:mm:ss | duration>
+	case "songat":
+		return b.songAtCommand(channel, messageSplit[1:])
+
+	// expected format: !setlist
+	case "setlist":
+		return b.setlistCommand(channel, message)
+
+	// expected format: !title
+	case "title":
+		return b.titleCommand(channel)
+
+	// expected format: !settitle <title>
+	case "settitle":
+		return b.setTitleCommand(channel, message, messageSplit[1:])
+
+	// expected format: !game
+	case "game":
+		return b.gameCommand(channel)
+
+	// expected format: !setgame <game>
+	case "setgame":
+		return b.setGameCommand(channel, message, messageSplit[1:])
+
+	// expected format: !uptime
+	case "uptime":
+		return b.uptimeCommand(channel, message)
+
+	// expected format: !followage (<user>)
+	case "followage":
+		return b.followAgeCommand(channel, message, messageSplit[1:])
+
+	// expected format: !accountage (<user>)
+	case "accountage":
+		return b.accountAgeCommand(channel, message, messageSplit[1:])
 
 	// Return any matching command output from database here.
 	default:
-		comm, err := b.Service.GetOneTwitchCommand(commName)
+		comm, err := b.Service.GetOneTwitchCommand(channel.Name, commName)
 		if err != nil {
 			if err == sql.ErrNoRows {
-				return fmt.Sprintf("Command %s not found.", commName)
+				return fmt.Sprintf("Command %s not found.", b.displayCommand(commName))
 			}
 			return err.Error()
 		}
 
-		if strings.ToLower(comm.Userlevel.String()) != "anyone" {
-			if !b.IsUserModOrOwner(message) {
-				return "You are not allowed to use that command."
-			}
+		if !channel.HasUserLevel(message, comm.Userlevel) {
+			return "You are not allowed to use that command."
 		}
 
-		// Check if command is still on cooldown.
-		if types.CheckCommandOnCooldown(comm.Name) {
-			return fmt.Sprintf("Command %s is still on cooldown.", comm.Name)
+		// Pay for the command first, Acquire() starts the cooldowns right away.
+		if comm.Cost > 0 {
+			if _, err := b.Service.SpendPoints(channel.Name, message.User.Name, comm.Cost); err != nil {
+				if err == database.ErrInsufficientPoints {
+					return fmt.Sprintf("Command %s costs %d %s.", b.displayCommand(comm.Name), comm.Cost, channel.Currency)
+				}
+				return err.Error()
+			}
 		}
 
-		// Add command to cooldown list in case it has a cooldown.
-		if err := types.SetCommandOnCooldown(comm.Name, comm.Cooldown); err != nil {
-			return err.Error()
+		// Mods may skip the cooldowns if the command allows it.
+		bypass := comm.ModBypass && channel.HasUserLevel(message, types.Moderator)
+
+		if !bypass {
+			// Check if command is still on cooldown, globally or for this user, and start the cooldowns if not.
+			remaining, onlyUser, blocked := b.cooldowns.Acquire(channel.Name, comm.Name, message.User.Name,
+				time.Duration(comm.Cooldown)*time.Second, time.Duration(comm.UserCooldown)*time.Second)
+
+			// Give the points back, the command did not run.
+			if blocked {
+				b.refundCommand(channel, message, comm)
+			}
+
+			if blocked && onlyUser {
+				return fmt.Sprintf("Command %s is ready for you in %s.", b.displayCommand(comm.Name), formatCooldown(remaining))
+			}
+
+			if blocked {
+				return fmt.Sprintf("Command %s is ready in %s.", b.displayCommand(comm.Name), formatCooldown(remaining))
+			}
 		}
 
 		var event database.AuthEvent
@@ -393,6 +448,7 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 			logging.WriteError(err)
 		}
 
+		event.Channel = channel.Name
 		event.Type = types.CommandCalled
 		event.Data = innerData
 		event.Timestamp = time.Now()
@@ -402,25 +458,62 @@ func (b *TwitchBot) commandHandler(message twitch.PrivateMessage) string {
 			logging.WriteError(err)
 		}
 
-		return comm.Output
+		// Count the call, ${count} includes this call.
+		uses, err := b.Service.IncrementTwitchCommandUses(channel.Name, comm.Name)
+		if err != nil {
+			logging.WriteError(err)
+			uses = comm.Uses + 1
+		}
+
+		output, err := b.renderOutput(channel, message, comm.Output, messageSplit[1:], uses)
+		if err != nil {
+			b.refundCommand(channel, message, comm)
+			return err.Error()
+		}
+
+		return output
+	}
+}
+
+// Gives the user the points back they paid for a command which did not answer.
+func (b *TwitchBot) refundCommand(channel *Channel, message twitch.PrivateMessage, comm database.TwitchCommand) {
+	if comm.Cost <= 0 {
+		return
+	}
+
+	if err := b.Service.AddPoints(channel.Name, map[string]int{message.User.Name: comm.Cost}); err != nil {
+		logging.WriteError(err)
 	}
 }
 
 // ! BUILT-IN COMMANDS / TWITCH BUILT-IN COMMANDS
 
-// Purges a user's last message.
-//
-// https://www.alphr.com/delete-single-message-twitch/
-func (b *TwitchBot) PurgeUser(username, reason string) {
-	b.Client.Say(b.Channel, fmt.Sprintf("/timeout %s 1s %s", username, reason))
+// Moderation actions are performed via the Helix API, Twitch does not support /timeout, /ban, ... via IRC anymore.
+
+// Purges (deletes) a single chat message.
+func (b *TwitchBot) PurgeMessage(channel *Channel, messageID string) {
+	b.queue.EnqueueAction(channel.Name, func() error {
+		return b.Helix.DeleteChatMessage(channel.ID(), messageID)
+	})
 }
 
 // Timeouts a user for duration X seconds.
-func (b *TwitchBot) TimeoutUser(username, reason string, duration int) {
-	b.Client.Say(b.Channel, fmt.Sprintf("/timeout %s %ds %s", username, duration, reason))
+func (b *TwitchBot) TimeoutUser(channel *Channel, userID, reason string, duration int) {
+	b.queue.EnqueueAction(channel.Name, func() error {
+		return b.Helix.BanUser(channel.ID(), userID, duration, reason)
+	})
 }
 
 // Permanently bans a user.
-func (b *TwitchBot) BanUser(username, reason string) {
-	b.Client.Say(b.Channel, fmt.Sprintf("/ban %s %s", username, reason))
+func (b *TwitchBot) BanUser(channel *Channel, userID, reason string) {
+	b.queue.EnqueueAction(channel.Name, func() error {
+		return b.Helix.BanUser(channel.ID(), userID, 0, reason)
+	})
+}
+
+// Removes a ban or timeout of a user.
+func (b *TwitchBot) UnbanUser(channel *Channel, userID string) {
+	b.queue.EnqueueAction(channel.Name, func() error {
+		return b.Helix.UnbanUser(channel.ID(), userID)
+	})
 }
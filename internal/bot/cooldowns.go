@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+)
+
+// How often expired cooldowns are removed from memory.
+const cooldownPruneInterval = time.Minute
+
+// Identifies a cooldown, user is empty for the global cooldown of a command.
+type cooldownKey struct {
+	channel string
+	command string
+	user    string
+}
+
+// Keeps track of global and per user command cooldowns.
+//
+// Safe to use from any goroutine. Cooldowns survive a restart if a database.Service was passed.
+type cooldownManager struct {
+	mu        sync.Mutex
+	expiry    map[cooldownKey]time.Time
+	lastPrune time.Time
+
+	// Stores the cooldowns if not nil.
+	service database.Service
+}
+
+// Inits a new cooldown manager, pass a nil service to keep the cooldowns in memory only.
+func newCooldownManager(svc database.Service) *cooldownManager {
+	m := cooldownManager{}
+
+	m.expiry = make(map[cooldownKey]time.Time)
+	m.lastPrune = time.Now()
+	m.service = svc
+
+	return &m
+}
+
+// Loads the still active cooldowns from the database and removes the expired ones.
+func (m *cooldownManager) Load() error {
+	if m.service == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	if err := m.service.DeleteExpiredCommandCooldowns(now); err != nil {
+		return err
+	}
+
+	cooldowns, err := m.service.GetActiveCommandCooldowns(now)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, cooldown := range cooldowns {
+		m.expiry[cooldownKey{cooldown.Channel, cooldown.Command, cooldown.Username}] = cooldown.ExpiresAt
+	}
+
+	return nil
+}
+
+// Returns how long the command is still on cooldown, 0 if it is ready.
+func (m *cooldownManager) Remaining(channel, command, user string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	remaining, _ := m.remaining(channel, command, user, time.Now())
+	return remaining
+}
+
+// Starts the global and per user cooldown of a command if it is not on cooldown.
+//
+// Returns the remaining time and true if the command is still on cooldown for the user,
+// onlyUser tells if only the user's cooldown is blocking. Checking and starting is atomic.
+func (m *cooldownManager) Acquire(channel, command, user string, global, perUser time.Duration) (remaining time.Duration, onlyUser bool, blocked bool) {
+	now := time.Now()
+	stored := []database.CommandCooldown{}
+
+	m.mu.Lock()
+
+	if remaining, onlyUser := m.remaining(channel, command, user, now); remaining > 0 {
+		m.mu.Unlock()
+		return remaining, onlyUser, true
+	}
+
+	if global > 0 {
+		m.expiry[cooldownKey{channel, command, ""}] = now.Add(global)
+		stored = append(stored, database.CommandCooldown{Channel: channel, Command: command, ExpiresAt: now.Add(global)})
+	}
+
+	if perUser > 0 {
+		m.expiry[cooldownKey{channel, command, user}] = now.Add(perUser)
+		stored = append(stored, database.CommandCooldown{Channel: channel, Command: command, Username: user, ExpiresAt: now.Add(perUser)})
+	}
+
+	m.prune(now)
+
+	m.mu.Unlock()
+
+	m.store(stored)
+
+	return 0, false, false
+}
+
+// Returns the longer remaining cooldown of the global and the user's cooldown. Lock needs to be held.
+func (m *cooldownManager) remaining(channel, command, user string, now time.Time) (time.Duration, bool) {
+	global := m.expiry[cooldownKey{channel, command, ""}].Sub(now)
+	perUser := m.expiry[cooldownKey{channel, command, user}].Sub(now)
+
+	if global <= 0 && perUser <= 0 {
+		return 0, false
+	}
+
+	if perUser > global {
+		return perUser, true
+	}
+
+	return global, false
+}
+
+// Removes expired cooldowns from memory every cooldownPruneInterval. Lock needs to be held.
+func (m *cooldownManager) prune(now time.Time) {
+	if now.Sub(m.lastPrune) < cooldownPruneInterval {
+		return
+	}
+
+	for key, expiry := range m.expiry {
+		if !expiry.After(now) {
+			delete(m.expiry, key)
+		}
+	}
+
+	m.lastPrune = now
+}
+
+// Persists the cooldowns if a database.Service was passed.
+func (m *cooldownManager) store(cooldowns []database.CommandCooldown) {
+	if m.service == nil {
+		return
+	}
+
+	for _, cooldown := range cooldowns {
+		if _, err := m.service.SetCommandCooldown(cooldown); err != nil {
+			logging.WriteError(err)
+		}
+	}
+}
+
+// Formats a remaining cooldown for chat, e.g. "42s" or "3m 5s".
+func formatCooldown(d time.Duration) string {
+	// Round up, "ready in 0s" would be confusing.
+	seconds := int((d + time.Second - 1) / time.Second)
+
+	switch {
+	case seconds >= 3600:
+		return fmt.Sprintf("%dh %dm", seconds/3600, seconds%3600/60)
+	case seconds >= 60:
+		return fmt.Sprintf("%dm %ds", seconds/60, seconds%60)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
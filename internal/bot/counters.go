@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Handles the built-in !counter command for named counters like a death counter.
+//
+// expected format: !counter <name> | !counter (set|add|remove) <name> (<value>) | !counter reset <name>
+func (b *TwitchBot) counterCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if len(args) == 0 {
+		return "Missing counter name."
+	}
+
+	action := strings.ToLower(args[0])
+
+	switch action {
+	case "set", "add", "remove", "reset":
+	default:
+		// Anyone may read a counter.
+		name := strings.ToLower(args[0])
+
+		counter, err := b.Service.GetCounter(channel.Name, name)
+		if err == sql.ErrNoRows {
+			return fmt.Sprintf("Counter %s is at 0.", name)
+		}
+		if err != nil {
+			return err.Error()
+		}
+
+		return fmt.Sprintf("Counter %s is at %d.", counter.Name, counter.Value)
+	}
+
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	if len(args) < 2 {
+		return "Missing counter name."
+	}
+
+	name := strings.ToLower(args[1])
+
+	// add and remove default to 1, set needs a value.
+	value := 1
+	if len(args) > 2 {
+		parsed, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Sprintf("Invalid value specified: %s", args[2])
+		}
+		value = parsed
+	} else if action == "set" {
+		return "Missing counter value."
+	}
+
+	var counter database.Counter
+	var err error
+
+	switch action {
+	case "set":
+		counter, err = b.Service.SetCounter(channel.Name, name, value)
+	case "reset":
+		counter, err = b.Service.SetCounter(channel.Name, name, 0)
+	case "add":
+		counter, err = b.Service.AdjustCounter(channel.Name, name, value)
+	case "remove":
+		counter, err = b.Service.AdjustCounter(channel.Name, name, -value)
+	}
+
+	if err != nil {
+		return err.Error()
+	}
+
+	b.recordEvent(channel.Name, types.CounterChanged, types.CounterEvent{
+		Issuer:  message.User.Name,
+		Counter: name,
+		Action:  action,
+		Value:   counter.Value,
+	})
+
+	return fmt.Sprintf("Counter %s is now at %d.", name, counter.Value)
+}
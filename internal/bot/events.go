@@ -0,0 +1,29 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/devusSs/twitch-kraken/internal/utils"
+)
+
+// Stores an auth event for the channel, errors are logged since the event should not stop the action.
+func (b *TwitchBot) recordEvent(channel string, eventType types.EventType, data interface{}) {
+	eventData, err := utils.MarshalStruct(data)
+	if err != nil {
+		logging.WriteError(err)
+		return
+	}
+
+	var event database.AuthEvent
+	event.Channel = channel
+	event.Type = eventType
+	event.Data = eventData
+	event.Timestamp = time.Now()
+
+	if _, err := b.Service.AddAuthEvent(event); err != nil {
+		logging.WriteError(err)
+	}
+}
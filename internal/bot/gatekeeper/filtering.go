@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
 	"github.com/devusSs/twitch-kraken/internal/logging"
 	"github.com/gempir/go-twitch-irc/v4"
 )
@@ -37,22 +38,11 @@ const (
 	SpammingLogReason gateKeeperLogReason = "BOT: spamming"
 )
 
-var (
-	// Users may sent 1 message per 2 seconds => 5 messages / 10 seconds.
-	//
-	// Every 2 seconds 1 message will be removed from this map.
-	//
-	// If the user exceeds a limit of 5 messages in 10 seconds they will receive a purge for spamming.
-	// TODO: add a settings option for that
-	// TODO: consider adding this to database, since a lot of users may result in a lot of ram usage
-	userMessagesOverTime map[string]int
-)
-
 // Checks the message sent on Twitch chat for any of the specified / applied filters.
 //
 // Returns either an empty string or a corresponding error string which can be sent back to Twitch chat.
 func (g *GateKeeper) FilterMessage(message twitch.PrivateMessage) (gateKeeperResult, gateKeeperReason, gateKeeperLogReason) {
-	handleMessageCountForUser(message.User.DisplayName)
+	g.handleMessageCountForUser(message.User.DisplayName)
 
 	filterEnabled := g.settings["filter_chat"]
 
@@ -60,6 +50,10 @@ func (g *GateKeeper) FilterMessage(message twitch.PrivateMessage) (gateKeeperRes
 	ignoreSubs := g.settings["ignore_subs"]
 
 	filterLinks := g.settings["filter_links"]
+	// Only subs (and mods) may chat in sub-only mode, so links do not need a permit.
+	if g.currentRoomState().SubsOnly {
+		filterLinks = false
+	}
 	maxSymbols := g.filterParams["symbols_max"]
 	maxEmojis := g.filterParams["emotes_max"]
 	badWords := g.badWords
@@ -72,7 +66,7 @@ func (g *GateKeeper) FilterMessage(message twitch.PrivateMessage) (gateKeeperRes
 		return NoneResult, NoneReason, NoneLogReason
 	}
 
-	if userSpams(message.User.DisplayName) {
+	if g.userSpams(message.User.DisplayName) {
 		return IssuePurge, SpammingReason, SpammingLogReason
 	}
 
@@ -109,7 +103,7 @@ func (g *GateKeeper) FilterMessage(message twitch.PrivateMessage) (gateKeeperRes
 // Function to check if a user is mod or owner of the bot.
 func isUserModOrBroadcaster(message twitch.PrivateMessage) bool {
 	user := message.User
-	if user.Badges["moderator"] == 1 || user.Badges["broadcaster"] == 1 {
+	if types.HasBadge(user.Badges, "moderator") || types.HasBadge(user.Badges, "broadcaster") {
 		return true
 	}
 	return false
@@ -118,7 +112,7 @@ func isUserModOrBroadcaster(message twitch.PrivateMessage) bool {
 // Function to check if a user is a subscriber to the channel.
 func isUserSubscriber(message twitch.PrivateMessage) bool {
 	user := message.User
-	return user.Badges["subscriber"] == 1
+	return types.IsSubscriber(user.Badges)
 }
 
 // Filter / Regex logic to check a message for links (https://, http://, www., ...).
@@ -191,19 +185,25 @@ func containsBadWord(message twitch.PrivateMessage, badWords []string) bool {
 // Adds 1 to the messages sent count for a user.
 //
 // Also sets up a timer to remove 1 of the message count of a user after 2 seconds.
-func handleMessageCountForUser(username string) {
-	userMessagesOverTime[username] = userMessagesOverTime[username] + 1
+func (g *GateKeeper) handleMessageCountForUser(username string) {
+	g.userMessagesMu.Lock()
+	g.userMessagesOverTime[username] = g.userMessagesOverTime[username] + 1
+	g.userMessagesMu.Unlock()
 
 	time.AfterFunc(2*time.Second, func() {
-		userMessagesOverTime[username] = userMessagesOverTime[username] - 1
+		g.userMessagesMu.Lock()
+		g.userMessagesOverTime[username] = g.userMessagesOverTime[username] - 1
+		g.userMessagesMu.Unlock()
 	})
 }
 
 // Checks the userMessagesOverTime map to check if the user exceeds the treshhold of 1 message per 2 seconds.
 //
 // Issues a purge for the user.
-func userSpams(username string) bool {
-	messageCount := userMessagesOverTime[username]
+func (g *GateKeeper) userSpams(username string) bool {
+	g.userMessagesMu.Lock()
+	messageCount := g.userMessagesOverTime[username]
+	g.userMessagesMu.Unlock()
 
 	return messageCount > 5
 }
@@ -2,30 +2,46 @@ package gatekeeper
 
 import (
 	"database/sql"
+	"sync"
 	"time"
 
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
 	"github.com/devusSs/twitch-kraken/internal/database"
 )
 
 // GateKeeper "Engine".
 type GateKeeper struct {
+	channel      string
 	owner        string
 	service      database.Service
 	settings     map[string]bool
 	filterParams map[string]int
 	badWords     []string
+	// Returns the current room state of the channel, may be nil.
+	roomState func() types.RoomState
+
+	// Users may sent 1 message per 2 seconds => 5 messages / 10 seconds.
+	//
+	// Every 2 seconds 1 message will be removed from this map.
+	//
+	// If the user exceeds a limit of 5 messages in 10 seconds they will receive a purge for spamming.
+	// TODO: add a settings option for that
+	// TODO: consider adding this to database, since a lot of users may result in a lot of ram usage
+	userMessagesOverTime map[string]int
+	userMessagesMu       sync.Mutex
 }
 
-// Init a new GateKeeper instance.
+// Init a new GateKeeper instance for the specified channel.
 //
 // # Will load default settings initially, load custom settings via LoadSettingsFromStore().
-func InitGateKeeper(owner string, svc database.Service) *GateKeeper {
-	userMessagesOverTime = make(map[string]int)
-
+func InitGateKeeper(channel, owner string, svc database.Service) *GateKeeper {
 	g := GateKeeper{}
 
+	g.channel = channel
 	g.owner = owner
 
+	g.userMessagesOverTime = make(map[string]int)
+
 	g.service = svc
 
 	g.settings = make(map[string]bool)
@@ -49,7 +65,7 @@ func InitGateKeeper(owner string, svc database.Service) *GateKeeper {
 //
 // # Loads default values if nothing was set on the database yet.
 func (g *GateKeeper) LoadSettingsFromStore() error {
-	settings, err := g.service.LoadGateKeeperSettings()
+	settings, err := g.service.LoadGateKeeperSettings(g.channel)
 	if err != nil {
 		// Use default values if no custom settings set on database.
 		if err == sql.ErrNoRows {
@@ -75,6 +91,7 @@ func (g *GateKeeper) LoadSettingsFromStore() error {
 func (g *GateKeeper) StoreInitialSettings() error {
 	s := database.GateKeeperSettings{}
 
+	s.Channel = g.channel
 	s.FilterChat = g.settings["filter_chat"]
 	s.FilterLinks = g.settings["filter_links"]
 	s.IgnoreMods = g.settings["ignore_mods"]
@@ -87,4 +104,17 @@ func (g *GateKeeper) StoreInitialSettings() error {
 	return g.service.UpdateGateKeeperSettings(s)
 }
 
+// Sets the function the Gatekeeper uses to look up the current room state (sub-only mode, ...).
+func (g *GateKeeper) SetRoomStateProvider(provider func() types.RoomState) {
+	g.roomState = provider
+}
+
+// Returns the current room state or the default room state if no provider was set.
+func (g *GateKeeper) currentRoomState() types.RoomState {
+	if g.roomState == nil {
+		return types.DefaultRoomState()
+	}
+	return g.roomState()
+}
+
 // TODO: add function to change settings
@@ -0,0 +1,478 @@
+package bot
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/helix"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/devusSs/twitch-kraken/internal/utils"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Entry of a draw's pool, stored as json in the audit record.
+type giveawayTicket struct {
+	Username string `json:"username"`
+	Tickets  int    `json:"tickets"`
+}
+
+// Loads the giveaways which still accept entries, e.g. after a restart.
+func (b *TwitchBot) loadOpenGiveaways() error {
+	for _, channel := range b.Channels {
+		giveaway, err := b.Service.GetOpenGiveaway(channel.Name)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		channel.setOpenGiveaway(&giveaway)
+
+		entries, err := b.Service.GetGiveawayEntries(giveaway.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			channel.markGiveawayEntrant(entry.Username)
+		}
+	}
+
+	return nil
+}
+
+// Splits the words of a !giveaway start message into the giveaway settings.
+//
+// Supported flags: -followers=<true|false>, -subs=<true|false>, -msgs=<count>, -seen=<YYYY-MM-DD>, -subluck=<tickets>
+func parseGiveawayFlags(giveaway *database.Giveaway, words []string) error {
+	for _, word := range words {
+		key, value, _ := strings.Cut(word, "=")
+
+		switch key {
+		case "-followers":
+			followers, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("Invalid followers specified: %s", value)
+			}
+			giveaway.FollowersOnly = followers
+
+		case "-subs":
+			subs, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("Invalid subs specified: %s", value)
+			}
+			giveaway.SubsOnly = subs
+
+		case "-msgs":
+			msgs, err := strconv.Atoi(value)
+			if err != nil || msgs < 0 {
+				return fmt.Errorf("Invalid message count specified: %s", value)
+			}
+			giveaway.MinMessages = msgs
+
+		case "-seen":
+			seen, err := time.ParseInLocation("2006-01-02", value, time.Local)
+			if err != nil {
+				return fmt.Errorf("Invalid date specified: %s (expected YYYY-MM-DD)", value)
+			}
+			giveaway.SeenBefore = sql.NullTime{Time: seen, Valid: true}
+
+		case "-subluck":
+			luck, err := strconv.Atoi(value)
+			if err != nil || luck < 1 {
+				return fmt.Errorf("Invalid sub luck specified: %s", value)
+			}
+			giveaway.SubLuck = luck
+
+		default:
+			return fmt.Errorf("Invalid option: %s", word)
+		}
+	}
+
+	return nil
+}
+
+// Handles the built-in !giveaway command, args are the words after "!giveaway".
+//
+// expected format:
+//
+//	!giveaway start <keyword> (-followers=true) (-subs=true) (-msgs=<count>) (-seen=<YYYY-MM-DD>) (-subluck=<tickets>)
+//	!giveaway close
+//	!giveaway draw
+//	!giveaway redraw
+//	!giveaway
+func (b *TwitchBot) giveawayCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if len(args) == 0 {
+		return b.giveawayStatus(channel)
+	}
+
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "start":
+		if len(args) < 2 {
+			return "Missing giveaway keyword."
+		}
+
+		if open, ok := channel.OpenGiveaway(); ok {
+			return fmt.Sprintf("Giveaway with keyword %s is still open, close or draw it first.", open.Keyword)
+		}
+
+		giveaway := database.Giveaway{}
+		giveaway.Channel = channel.Name
+		giveaway.Keyword = strings.ToLower(args[1])
+		giveaway.SubLuck = 1
+		giveaway.StartedBy = message.User.Name
+		giveaway.Started = time.Now()
+
+		if err := parseGiveawayFlags(&giveaway, args[2:]); err != nil {
+			return err.Error()
+		}
+
+		giveaway, err := b.Service.AddGiveaway(giveaway)
+		if err != nil {
+			return err.Error()
+		}
+
+		channel.setOpenGiveaway(&giveaway)
+
+		b.recordEvent(channel.Name, types.GiveawayStarted, types.GiveawayEvent{
+			Issuer:     message.User.Name,
+			GiveawayID: giveaway.ID,
+			Keyword:    giveaway.Keyword,
+		})
+
+		return fmt.Sprintf("Giveaway started! Type %s in chat to enter.", giveaway.Keyword)
+
+	case "close":
+		giveaway, ok := channel.OpenGiveaway()
+		if !ok {
+			return "There is no open giveaway."
+		}
+
+		if err := b.closeGiveaway(channel, giveaway, message.User.Name); err != nil {
+			return err.Error()
+		}
+
+		return "Giveaway entries are closed."
+
+	case "draw", "redraw":
+		return b.drawGiveaway(channel, message, strings.ToLower(args[0]) == "redraw")
+
+	default:
+		return fmt.Sprintf("Invalid subcommand: %s", args[0])
+	}
+}
+
+// Returns the keyword and entry count of the open giveaway, or the winner of the last one.
+func (b *TwitchBot) giveawayStatus(channel *Channel) string {
+	giveaway, err := b.Service.GetLatestGiveaway(channel.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "There is no giveaway yet."
+		}
+		return err.Error()
+	}
+
+	entries, err := b.Service.GetGiveawayEntries(giveaway.ID)
+	if err != nil {
+		return err.Error()
+	}
+
+	if !giveaway.Closed.Valid {
+		return fmt.Sprintf("Giveaway is open, type %s in chat to enter (%d entries).", giveaway.Keyword, len(entries))
+	}
+
+	draws, err := b.Service.GetGiveawayDraws(giveaway.ID)
+	if err != nil {
+		return err.Error()
+	}
+
+	if len(draws) == 0 {
+		return fmt.Sprintf("Giveaway is closed with %d entries, waiting for the draw.", len(entries))
+	}
+
+	return fmt.Sprintf("Last giveaway was won by %s.", draws[len(draws)-1].Winner)
+}
+
+// Stops accepting entries for the giveaway.
+func (b *TwitchBot) closeGiveaway(channel *Channel, giveaway database.Giveaway, issuer string) error {
+	if err := b.Service.CloseGiveaway(giveaway.ID, time.Now()); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	channel.setOpenGiveaway(nil)
+
+	b.recordEvent(channel.Name, types.GiveawayClosed, types.GiveawayEvent{
+		Issuer:     issuer,
+		GiveawayID: giveaway.ID,
+		Keyword:    giveaway.Keyword,
+	})
+
+	return nil
+}
+
+// Draws a winner of the latest giveaway, closing it if it is still open.
+//
+// Previous winners and banned users can not win, timed out users can. Every draw is stored with its seed and the pool
+// it was drawn from, banned winners are not part of it.
+func (b *TwitchBot) drawGiveaway(channel *Channel, message twitch.PrivateMessage, redraw bool) string {
+	giveaway, err := b.Service.GetLatestGiveaway(channel.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "There is no giveaway yet."
+		}
+		return err.Error()
+	}
+
+	draws, err := b.Service.GetGiveawayDraws(giveaway.ID)
+	if err != nil {
+		return err.Error()
+	}
+
+	if !redraw && len(draws) > 0 {
+		return fmt.Sprintf("Giveaway was already won by %s, use %s to draw again.",
+			draws[len(draws)-1].Winner, b.displayCommand("giveaway redraw"))
+	}
+
+	if redraw && len(draws) == 0 {
+		return fmt.Sprintf("Giveaway has not been drawn yet, use %s.", b.displayCommand("giveaway draw"))
+	}
+
+	if !giveaway.Closed.Valid {
+		if err := b.closeGiveaway(channel, giveaway, message.User.Name); err != nil {
+			return err.Error()
+		}
+	}
+
+	entries, err := b.Service.GetGiveawayEntries(giveaway.ID)
+	if err != nil {
+		return err.Error()
+	}
+
+	excluded := make(map[string]bool)
+	for _, draw := range draws {
+		excluded[draw.Winner] = true
+	}
+
+	pool := []giveawayTicket{}
+	names := make(map[string]string)
+	total := 0
+
+	for _, entry := range entries {
+		if excluded[entry.Username] {
+			continue
+		}
+		pool = append(pool, giveawayTicket{Username: entry.Username, Tickets: entry.Tickets})
+		names[entry.Username] = entry.DisplayName
+		total += entry.Tickets
+	}
+
+	var seed int64
+	var winner string
+
+	// Users may have been banned after they entered, banned winners are removed from the pool and drawn again.
+	for {
+		if total == 0 {
+			return "There are no eligible entries left."
+		}
+
+		seed, err = newSeed()
+		if err != nil {
+			return err.Error()
+		}
+
+		winner = pickWinner(pool, seed)
+
+		if !b.isPermanentlyBanned(channel, winner) {
+			break
+		}
+
+		for i, entry := range pool {
+			if entry.Username == winner {
+				total -= entry.Tickets
+				pool = append(pool[:i], pool[i+1:]...)
+				break
+			}
+		}
+	}
+
+	poolData, err := utils.MarshalStruct(pool)
+	if err != nil {
+		return err.Error()
+	}
+
+	draw := database.GiveawayDraw{}
+	draw.GiveawayID = giveaway.ID
+	draw.Winner = winner
+	draw.Seed = seed
+	draw.Pool = poolData
+	draw.Tickets = total
+	draw.Redraw = redraw
+	draw.DrawnBy = message.User.Name
+	draw.Drawn = time.Now()
+
+	if _, err := b.Service.AddGiveawayDraw(draw); err != nil {
+		return err.Error()
+	}
+
+	b.recordEvent(channel.Name, types.GiveawayDrawn, types.GiveawayEvent{
+		Issuer:     message.User.Name,
+		GiveawayID: giveaway.ID,
+		Keyword:    giveaway.Keyword,
+		Winner:     winner,
+		Seed:       seed,
+	})
+
+	return fmt.Sprintf("The winner is @%s! (%d eligible entries)", names[winner], len(pool))
+}
+
+// Returns true if the user is banned from the channel. Timeouts do not count, they end on their own.
+//
+// Returns false if the ban can not be checked, e.g. if the Helix token does not belong to the channel's broadcaster.
+func (b *TwitchBot) isPermanentlyBanned(channel *Channel, username string) bool {
+	if channel.ID() == "" {
+		return false
+	}
+
+	user, err := b.Service.GetTwitchUser(channel.Name, username)
+	if err != nil || user.TwitchID == "" {
+		return false
+	}
+
+	ban, err := b.Helix.GetBannedUser(channel.ID(), user.TwitchID)
+	if errors.Is(err, helix.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		logging.WriteWarn(fmt.Sprintf("Could not check if giveaway winner %s is banned in %s: %s", username, channel.Name, err.Error()))
+		return false
+	}
+
+	return ban.ExpiresAt.IsZero()
+}
+
+// Returns a random seed from crypto/rand, so draws can not be predicted.
+func newSeed() (int64, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(buf)), nil
+}
+
+// Picks a winner from the pool, every ticket has the same chance. The same seed and pool always pick the same winner.
+func pickWinner(pool []giveawayTicket, seed int64) string {
+	total := 0
+	for _, entry := range pool {
+		total += entry.Tickets
+	}
+
+	ticket := mathrand.New(mathrand.NewSource(seed)).Intn(total)
+
+	for _, entry := range pool {
+		if ticket < entry.Tickets {
+			return entry.Username
+		}
+		ticket -= entry.Tickets
+	}
+
+	return pool[len(pool)-1].Username
+}
+
+// Enters the user into the open giveaway if the message is its keyword.
+//
+// Returns true if the message was the keyword, the user is told if they are not eligible.
+func (b *TwitchBot) handleGiveawayEntry(channel *Channel, message twitch.PrivateMessage) bool {
+	giveaway, ok := channel.OpenGiveaway()
+	if !ok || !strings.EqualFold(strings.TrimSpace(message.Message), giveaway.Keyword) {
+		return false
+	}
+
+	// Entering twice does nothing.
+	if !channel.markGiveawayEntrant(message.User.Name) {
+		return true
+	}
+
+	subscriber := types.IsSubscriber(message.User.Badges)
+
+	if reason, err := b.giveawayIneligibility(channel, giveaway, message, subscriber); reason != "" || err != nil {
+		channel.unmarkGiveawayEntrant(message.User.Name)
+		if err != nil {
+			logging.WriteError(err)
+			reason = "Could not check if you can enter the giveaway, please try again."
+		}
+		b.Respond(ResponseCommand, message, reason)
+		return true
+	}
+
+	entry := database.GiveawayEntry{}
+	entry.GiveawayID = giveaway.ID
+	entry.Username = message.User.Name
+	entry.DisplayName = message.User.DisplayName
+	entry.Tickets = 1
+	entry.Entered = time.Now()
+
+	if subscriber {
+		entry.Tickets = giveaway.SubLuck
+	}
+
+	if err := b.Service.AddGiveawayEntry(entry); err != nil && err != sql.ErrTxDone {
+		channel.unmarkGiveawayEntrant(message.User.Name)
+		logging.WriteError(err)
+	}
+
+	return true
+}
+
+// Returns why the user may not enter the giveaway, empty if they may.
+func (b *TwitchBot) giveawayIneligibility(channel *Channel, giveaway database.Giveaway, message twitch.PrivateMessage, subscriber bool) (string, error) {
+	if giveaway.SubsOnly && !subscriber {
+		return "This giveaway is for subscribers only.", nil
+	}
+
+	if giveaway.MinMessages > 0 {
+		count, err := b.Service.CountMessageEvents(channel.Name, message.User.Name)
+		if err != nil {
+			return "", err
+		}
+		if count < giveaway.MinMessages {
+			return fmt.Sprintf("You need to have sent at least %d chat messages to enter.", giveaway.MinMessages), nil
+		}
+	}
+
+	if giveaway.SeenBefore.Valid {
+		user, err := b.Service.GetTwitchUser(channel.Name, message.User.Name)
+		if err != nil && err != sql.ErrNoRows {
+			return "", err
+		}
+		if !user.FirstSeen.Valid || !user.FirstSeen.Time.Before(giveaway.SeenBefore.Time) {
+			return fmt.Sprintf("You need to have been here before %s to enter.", giveaway.SeenBefore.Time.Format("2006-01-02")), nil
+		}
+	}
+
+	// The broadcaster can not follow their own channel.
+	if giveaway.FollowersOnly && !channel.HasUserLevel(message, types.Broadcaster) {
+		_, err := b.Helix.GetChannelFollower(channel.ID(), message.User.ID)
+		if errors.Is(err, helix.ErrNotFound) {
+			return "This giveaway is for followers only.", nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
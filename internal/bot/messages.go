@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/utils"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Marks a stored chat message as deleted and records who deleted it (bot or human mod).
+func (b *TwitchBot) RecordDeletedMessage(channel, target, messageID, content string, byBot bool) error {
+	err := b.Service.MarkMessageEventDeleted(messageID, byBot, time.Now())
+	if err != nil {
+		// The message was either not stored or already marked as deleted by the bot.
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	eventData, err := utils.MarshalStruct(types.MessageDeleteEvent{
+		Channel:   channel,
+		Target:    target,
+		MessageID: messageID,
+		Content:   content,
+		ByBot:     byBot,
+	})
+	if err != nil {
+		return err
+	}
+
+	var event database.AuthEvent
+	event.Channel = channel
+	event.Type = types.MessageDeleted
+	event.Data = eventData
+	event.Timestamp = time.Now()
+
+	_, err = b.Service.AddAuthEvent(event)
+
+	return err
+}
+
+// Handles CLEARMSG events (a single message was deleted).
+//
+// Twitch does not tell us which mod deleted the message, messages deleted by the bot are marked beforehand.
+func (b *TwitchBot) HandleClearMessage(message twitch.ClearMessage) error {
+	return b.RecordDeletedMessage(message.Channel, message.Login, message.TargetMsgID, message.Message, false)
+}
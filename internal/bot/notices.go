@@ -0,0 +1,293 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/devusSs/twitch-kraken/internal/utils"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Parsed USERNOTICE event, ready to be stored and answered.
+type userNotice struct {
+	eventType types.EventType
+	data      interface{}
+	// Variables which may be used in the corresponding thank-you template.
+	vars map[string]string
+	// Ids which link a mystery gift to the single gift subs it consists of (msg-param-community-gift-id, msg-param-origin-id).
+	giftIDs []string
+}
+
+// How long the ids of a mystery gift are remembered, its gift subs follow within seconds.
+const mysteryGiftWindow = 5 * time.Minute
+
+// Remembers mystery gifts which were thanked, so their single gift subs are not thanked again.
+type mysteryGifts struct {
+	mu  sync.Mutex
+	ids map[string]time.Time
+}
+
+// Remembers the ids of a thanked mystery gift and forgets expired ones.
+func (g *mysteryGifts) add(ids []string) {
+	now := time.Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.ids == nil {
+		g.ids = make(map[string]time.Time)
+	}
+
+	for id, added := range g.ids {
+		if now.Sub(added) > mysteryGiftWindow {
+			delete(g.ids, id)
+		}
+	}
+
+	for _, id := range ids {
+		g.ids[id] = now
+	}
+}
+
+// Returns true if one of the ids belongs to a mystery gift which was thanked.
+func (g *mysteryGifts) contains(ids []string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, id := range ids {
+		if added, ok := g.ids[id]; ok && time.Since(added) <= mysteryGiftWindow {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handles USERNOTICE events like subs, resubs, gift subs, raids and announcements.
+//
+// Stores the event on the database and sends the configured thank-you message (if any).
+func (b *TwitchBot) HandleUserNotice(message twitch.UserNoticeMessage) error {
+	notice, ok := parseUserNotice(message)
+	if !ok {
+		logging.WriteInfo(fmt.Sprintf("Ignoring unsupported user notice \"%s\"", message.MsgID))
+		return nil
+	}
+
+	// A mystery gift of N subs is followed by N gift sub notices, only the mystery gift is thanked.
+	thank := true
+	if gift, ok := notice.data.(types.GiftSubEvent); ok && (gift.CommunityGift || b.mysteryGifts.contains(notice.giftIDs)) {
+		gift.CommunityGift = true
+		notice.data = gift
+		thank = false
+	}
+
+	if notice.eventType == types.UserMysteryGift {
+		b.mysteryGifts.add(notice.giftIDs)
+	}
+
+	eventData, err := utils.MarshalStruct(notice.data)
+	if err != nil {
+		return err
+	}
+
+	var event database.AuthEvent
+	event.Channel = message.Channel
+	event.Type = notice.eventType
+	event.Data = eventData
+	event.Timestamp = time.Now()
+
+	if _, err := b.Service.AddAuthEvent(event); err != nil {
+		return err
+	}
+
+	template := b.Notices[notice.eventType]
+	if template == "" || !thank {
+		return nil
+	}
+
+	b.SendMessage(message.Channel, renderNoticeTemplate(template, notice.vars))
+
+	return nil
+}
+
+// Converts a USERNOTICE message into a typed event depending on its msg-id.
+//
+// Returns false if the msg-id is not supported.
+func parseUserNotice(message twitch.UserNoticeMessage) (userNotice, bool) {
+	params := message.MsgParams
+
+	user := message.User.DisplayName
+	if user == "" {
+		user = message.Tags["login"]
+	}
+
+	tier := subTier(params["msg-param-sub-plan"])
+
+	giftIDs := []string{}
+	for _, key := range []string{"msg-param-community-gift-id", "msg-param-origin-id"} {
+		if id := params[key]; id != "" {
+			giftIDs = append(giftIDs, id)
+		}
+	}
+
+	switch message.MsgID {
+	case "sub", "resub":
+		sub := types.SubEvent{
+			Channel:          message.Channel,
+			User:             user,
+			Tier:             tier,
+			CumulativeMonths: paramInt(params, "msg-param-cumulative-months"),
+			Message:          message.Message,
+		}
+
+		// The streak is only sent if the user wants to share it.
+		if params["msg-param-should-share-streak"] == "1" {
+			sub.StreakMonths = paramInt(params, "msg-param-streak-months")
+		}
+
+		eventType := types.UserSub
+		if message.MsgID == "resub" {
+			eventType = types.UserResub
+		}
+
+		return userNotice{eventType, sub, map[string]string{
+			"user":   sub.User,
+			"tier":   sub.Tier,
+			"months": strconv.Itoa(sub.CumulativeMonths),
+			"streak": strconv.Itoa(sub.StreakMonths),
+		}, nil}, true
+
+	case "subgift", "anonsubgift":
+		gift := types.GiftSubEvent{
+			Channel:   message.Channel,
+			Gifter:    user,
+			Recipient: params["msg-param-recipient-display-name"],
+			Tier:      tier,
+			Months:    paramInt(params, "msg-param-gift-months"),
+			Anonymous: message.MsgID == "anonsubgift",
+			// Only gift subs of a mystery gift carry the community gift id.
+			CommunityGift: params["msg-param-community-gift-id"] != "",
+		}
+
+		if gift.Anonymous {
+			gift.Gifter = "An anonymous gifter"
+		}
+
+		return userNotice{types.UserSubGift, gift, map[string]string{
+			"user":      gift.Gifter,
+			"recipient": gift.Recipient,
+			"tier":      gift.Tier,
+			"months":    strconv.Itoa(gift.Months),
+		}, giftIDs}, true
+
+	case "submysterygift", "anonsubmysterygift":
+		gift := types.MysteryGiftEvent{
+			Channel:     message.Channel,
+			Gifter:      user,
+			Tier:        tier,
+			Count:       paramInt(params, "msg-param-mass-gift-count"),
+			SenderTotal: paramInt(params, "msg-param-sender-count"),
+			Anonymous:   message.MsgID == "anonsubmysterygift",
+		}
+
+		if gift.Anonymous {
+			gift.Gifter = "An anonymous gifter"
+		}
+
+		return userNotice{types.UserMysteryGift, gift, map[string]string{
+			"user":  gift.Gifter,
+			"tier":  gift.Tier,
+			"count": strconv.Itoa(gift.Count),
+		}, giftIDs}, true
+
+	case "giftpaidupgrade", "anongiftpaidupgrade", "primepaidupgrade":
+		upgrade := types.GiftUpgradeEvent{
+			Channel: message.Channel,
+			User:    user,
+			Gifter:  params["msg-param-sender-name"],
+			Tier:    tier,
+		}
+
+		if message.MsgID == "primepaidupgrade" {
+			upgrade.Gifter = "Prime"
+		}
+
+		return userNotice{types.UserGiftUpgrade, upgrade, map[string]string{
+			"user": upgrade.User,
+			"tier": upgrade.Tier,
+		}, nil}, true
+
+	case "raid":
+		raid := types.RaidEvent{
+			Channel: message.Channel,
+			Raider:  params["msg-param-displayName"],
+			Viewers: paramInt(params, "msg-param-viewerCount"),
+		}
+
+		if raid.Raider == "" {
+			raid.Raider = user
+		}
+
+		return userNotice{types.ChannelRaid, raid, map[string]string{
+			"user":    raid.Raider,
+			"viewers": strconv.Itoa(raid.Viewers),
+		}, nil}, true
+
+	case "announcement":
+		announcement := types.AnnouncementEvent{
+			Channel: message.Channel,
+			User:    user,
+			Color:   params["msg-param-color"],
+			Message: message.Message,
+		}
+
+		return userNotice{types.ChannelAnnounced, announcement, map[string]string{
+			"user": announcement.User,
+		}, nil}, true
+
+	default:
+		return userNotice{}, false
+	}
+}
+
+// Replaces the {variable} placeholders of a notice template with their values.
+func renderNoticeTemplate(template string, vars map[string]string) string {
+	oldNew := []string{}
+
+	for key, value := range vars {
+		oldNew = append(oldNew, fmt.Sprintf("{%s}", key), value)
+	}
+
+	return strings.NewReplacer(oldNew...).Replace(template)
+}
+
+// Converts Twitch's sub plan (Prime, 1000, 2000, 3000) to a human readable tier.
+func subTier(plan string) string {
+	switch plan {
+	case "Prime":
+		return "Prime"
+	case "1000":
+		return "Tier 1"
+	case "2000":
+		return "Tier 2"
+	case "3000":
+		return "Tier 3"
+	default:
+		return plan
+	}
+}
+
+// Reads a numeric msg-param, returns 0 if the param is missing or invalid.
+func paramInt(params map[string]string, key string) int {
+	value, err := strconv.Atoi(params[key])
+	if err != nil {
+		return 0
+	}
+	return value
+}
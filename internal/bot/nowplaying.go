@@ -0,0 +1,233 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/config"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/helix"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/devusSs/twitch-kraken/internal/spotify"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Polls the Spotify account, stores every track in the song history and announces track changes in chat.
+type nowPlayingPoller struct {
+	bot *TwitchBot
+	// Channel of the Spotify account's owner, the song history belongs to its streams.
+	channel  string
+	interval time.Duration
+	// Minimum time between two announcements in a channel.
+	announceGap time.Duration
+
+	mu sync.Mutex
+	// Track which was playing at the last poll, empty if nothing was playing.
+	lastTrack string
+	// Whether announcements are on, keyed by channel name.
+	announce map[string]bool
+	// When the last track was announced, keyed by channel name.
+	lastAnnounced map[string]time.Time
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// Inits and starts a new now playing poller.
+func newNowPlayingPoller(bot *TwitchBot, cfg *config.Config) *nowPlayingPoller {
+	p := nowPlayingPoller{}
+
+	p.bot = bot
+	p.channel = cfg.SpotifyAuth.Channel
+	p.interval = time.Duration(cfg.NowPlaying.Interval) * time.Second
+	p.announceGap = time.Duration(cfg.NowPlaying.AnnounceGap) * time.Second
+	p.announce = make(map[string]bool)
+	p.lastAnnounced = make(map[string]time.Time)
+	p.done = make(chan struct{})
+	p.stopped = make(chan struct{})
+
+	for _, channel := range cfg.Twitch.Channels {
+		p.announce[channel.Name] = channel.AnnounceSongs
+	}
+
+	go p.run()
+
+	return &p
+}
+
+// Stops the poller and waits for it to finish.
+func (p *nowPlayingPoller) Stop() {
+	close(p.done)
+	<-p.stopped
+}
+
+func (p *nowPlayingPoller) run() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.tick(); err != nil {
+				logging.WriteError(err)
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Stores and announces the current track if it changed since the last poll.
+func (p *nowPlayingPoller) tick() error {
+	playing, err := p.bot.Spotify.CurrentlyPlaying()
+	if errors.Is(err, spotify.ErrNothingPlaying) {
+		p.mu.Lock()
+		p.lastTrack = ""
+		p.mu.Unlock()
+		return nil
+	}
+	// Ads do not end the track before them.
+	if errors.Is(err, spotify.ErrAdPlaying) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	changed := playing.Track.ID != p.lastTrack
+	p.lastTrack = playing.Track.ID
+	p.mu.Unlock()
+
+	if !changed {
+		return nil
+	}
+
+	if err := p.store(playing); err != nil {
+		logging.WriteError(fmt.Errorf("could not store %s in the song history: %w", playing.Track, err))
+	}
+
+	for _, channel := range p.bot.Channels {
+		if err := p.announceIn(channel, playing.Track); err != nil {
+			logging.WriteError(err)
+		}
+	}
+
+	return nil
+}
+
+// Adds the track to the song history, along with the stream it was played in.
+func (p *nowPlayingPoller) store(playing spotify.Playing) error {
+	song := database.SongHistory{
+		Channel: p.channel,
+		TrackID: playing.Track.ID,
+		Title:   playing.Track.Name,
+		Artists: playing.Track.ArtistNames(),
+		URL:     playing.Track.ExternalURLs.Spotify,
+		Started: playing.RetrievedAt.Add(-playing.Progress),
+	}
+
+	if channel, ok := p.bot.Channels[p.channel]; ok {
+		started, err := p.bot.streamStart(channel)
+		if err != nil {
+			return err
+		}
+		song.StreamStarted = started
+	}
+
+	return p.bot.Service.AddSongHistory(song)
+}
+
+// Posts the track in the channel if announcements are on, the last one is long enough ago and the channel is live.
+func (p *nowPlayingPoller) announceIn(channel *Channel, track spotify.Track) error {
+	p.mu.Lock()
+	due := p.announce[channel.Name] && time.Since(p.lastAnnounced[channel.Name]) >= p.announceGap
+	p.mu.Unlock()
+
+	if !due {
+		return nil
+	}
+
+	live, err := p.bot.isLive(channel)
+	if err != nil || !live {
+		return err
+	}
+
+	p.mu.Lock()
+	p.lastAnnounced[channel.Name] = time.Now()
+	p.mu.Unlock()
+
+	p.bot.SendMessage(channel.Name, fmt.Sprintf("Now playing: %s %s", track, track.ExternalURLs.Spotify))
+
+	return nil
+}
+
+// Turns announcements in the channel on or off.
+func (p *nowPlayingPoller) setAnnounce(channel string, on bool) {
+	p.mu.Lock()
+	p.announce[channel] = on
+	p.mu.Unlock()
+}
+
+// Returns whether announcements in the channel are on.
+func (p *nowPlayingPoller) announces(channel string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.announce[channel]
+}
+
+// Returns when the channel's current stream started, null if the channel is offline.
+//
+// The time is converted to local time like every other timestamp the bot stores.
+func (b *TwitchBot) streamStart(channel *Channel) (sql.NullTime, error) {
+	if channel.ID() == "" {
+		return sql.NullTime{}, nil
+	}
+
+	stream, err := b.Helix.GetStream(channel.ID())
+	if errors.Is(err, helix.ErrNotFound) {
+		return sql.NullTime{}, nil
+	}
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("could not get the stream of %s: %w", channel.Name, err)
+	}
+
+	return sql.NullTime{Time: stream.StartedAt.Local(), Valid: true}, nil
+}
+
+// Handles the built-in !nowplaying command, mods turn the announcements of track changes on or off.
+//
+// expected format: !nowplaying (on | off)
+func (b *TwitchBot) nowPlayingCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	if b.nowPlaying == nil {
+		return "Now playing announcements are disabled."
+	}
+
+	if len(args) == 0 {
+		if b.nowPlaying.announces(channel.Name) {
+			return "Now playing announcements are on."
+		}
+		return "Now playing announcements are off."
+	}
+
+	switch args[0] {
+	case "on":
+		b.nowPlaying.setAnnounce(channel.Name, true)
+		return "Now playing announcements turned on."
+	case "off":
+		b.nowPlaying.setAnnounce(channel.Name, false)
+		return "Now playing announcements turned off."
+	default:
+		return fmt.Sprintf("Invalid subcommand: %s", args[0])
+	}
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/template"
+	"github.com/devusSs/twitch-kraken/internal/helix"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Renders the variables (${user}, ${random 1-100}, ...) of a custom command's output.
+//
+// uses is the usage count of the command, available as ${count}.
+func (b *TwitchBot) renderOutput(channel *Channel, message twitch.PrivateMessage, output string, args []string, uses int) (string, error) {
+	ctx := template.Context{
+		User:    message.User.DisplayName,
+		Args:    args,
+		Channel: channel.Name,
+		RandomUser: func() (string, error) {
+			name, ok := channel.RandomChatter()
+			if !ok {
+				return message.User.DisplayName, nil
+			}
+			return name, nil
+		},
+		Count: func(name string) (int, error) {
+			if name == "" {
+				return uses, nil
+			}
+
+			counter, err := b.Service.GetCounter(channel.Name, name)
+			if err == sql.ErrNoRows {
+				return 0, nil
+			}
+			return counter.Value, err
+		},
+		Uptime: func() (time.Duration, error) {
+			return b.streamUptime(channel)
+		},
+	}
+
+	return template.Render(output, ctx)
+}
+
+// Returns how long the channel has been live, template.ErrOffline if it is offline.
+func (b *TwitchBot) streamUptime(channel *Channel) (time.Duration, error) {
+	if channel.ID() == "" {
+		return 0, fmt.Errorf("channel id of %s is not known yet", channel.Name)
+	}
+
+	stream, err := b.Helix.GetStream(channel.ID())
+	if errors.Is(err, helix.ErrNotFound) {
+		return 0, template.ErrOffline
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Since(stream.StartedAt), nil
+}
@@ -0,0 +1,344 @@
+package bot
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/config"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+const (
+	// How often the points manager checks if the channels are live.
+	pointsTick = time.Minute
+
+	// Users shown by !top.
+	topPointsLimit = 5
+)
+
+// Identifies a user of a channel.
+type channelUser struct {
+	channel  string
+	username string
+}
+
+// Hands out loyalty points for watch time and chat messages while a channel is live.
+type pointsManager struct {
+	bot *TwitchBot
+
+	watchPoints   int
+	watchInterval time.Duration
+	chatPoints    int
+	chatCooldown  time.Duration
+	subMultiplier float64
+	vipMultiplier float64
+
+	mu sync.Mutex
+	// Live state per channel, refreshed every pointsTick.
+	live map[string]bool
+	// When watch points were handed out last per channel, missing while offline.
+	lastAward map[string]time.Time
+	// Multiplier of each user, known from the badges of their last chat message.
+	multipliers map[channelUser]float64
+	// When a user's chat message earned points last.
+	lastChat map[channelUser]time.Time
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// Inits and starts a new points manager.
+func newPointsManager(bot *TwitchBot, cfg *config.Config) *pointsManager {
+	m := pointsManager{}
+
+	m.bot = bot
+	m.watchPoints = cfg.Points.WatchPoints
+	m.watchInterval = time.Duration(cfg.Points.WatchInterval) * time.Minute
+	m.chatPoints = cfg.Points.ChatPoints
+	m.chatCooldown = time.Duration(cfg.Points.ChatCooldown) * time.Second
+	m.subMultiplier = cfg.Points.SubMultiplier
+	m.vipMultiplier = cfg.Points.VIPMultiplier
+	m.live = make(map[string]bool)
+	m.lastAward = make(map[string]time.Time)
+	m.multipliers = make(map[channelUser]float64)
+	m.lastChat = make(map[channelUser]time.Time)
+	m.done = make(chan struct{})
+	m.stopped = make(chan struct{})
+
+	go m.run()
+
+	return &m
+}
+
+// Stops the manager and waits for it to finish.
+func (m *pointsManager) Stop() {
+	close(m.done)
+	<-m.stopped
+}
+
+func (m *pointsManager) run() {
+	defer close(m.stopped)
+
+	ticker := time.NewTicker(pointsTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, channel := range m.bot.Channels {
+				if err := m.tick(channel); err != nil {
+					logging.WriteError(err)
+				}
+			}
+			m.prune()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// Refreshes the live state of the channel and hands out the watch points if they are due.
+func (m *pointsManager) tick(channel *Channel) error {
+	live, err := m.bot.isLive(channel)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	m.mu.Lock()
+	m.live[channel.Name] = live
+
+	if !live {
+		delete(m.lastAward, channel.Name)
+		m.mu.Unlock()
+		return nil
+	}
+
+	// The first points are handed out a full interval after the stream went live.
+	lastAward, ok := m.lastAward[channel.Name]
+	if !ok || now.Sub(lastAward) < m.watchInterval || m.watchPoints == 0 {
+		if !ok {
+			m.lastAward[channel.Name] = now
+		}
+		m.mu.Unlock()
+		return nil
+	}
+
+	m.lastAward[channel.Name] = now
+	m.mu.Unlock()
+
+	viewers, err := m.bot.Service.GetOpenViewerSessions(channel.Name)
+	if err != nil {
+		return err
+	}
+
+	amounts := make(map[string]int)
+
+	m.mu.Lock()
+	for _, viewer := range viewers {
+		amounts[viewer] = m.apply(m.watchPoints, channelUser{channel.Name, viewer})
+	}
+	m.mu.Unlock()
+
+	return m.bot.Service.AddPoints(channel.Name, amounts)
+}
+
+// Removes chat timestamps which are past their cooldown.
+func (m *pointsManager) prune() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for user, last := range m.lastChat {
+		if time.Since(last) >= m.chatCooldown {
+			delete(m.lastChat, user)
+		}
+	}
+}
+
+// Returns the points multiplied with the user's multiplier, rounded. Needs m.mu.
+func (m *pointsManager) apply(points int, user channelUser) int {
+	multiplier, ok := m.multipliers[user]
+	if !ok {
+		multiplier = 1
+	}
+	return int(math.Round(float64(points) * multiplier))
+}
+
+// Remembers the multiplier of the chat user and hands out chat points if the channel is live.
+func (m *pointsManager) CountMessage(channel *Channel, message twitch.PrivateMessage) {
+	user := channelUser{channel.Name, message.User.Name}
+	now := time.Now()
+
+	multiplier := 1.0
+	if types.IsSubscriber(message.User.Badges) {
+		multiplier = math.Max(multiplier, m.subMultiplier)
+	}
+	if types.HasBadge(message.User.Badges, "vip") {
+		multiplier = math.Max(multiplier, m.vipMultiplier)
+	}
+
+	m.mu.Lock()
+	m.multipliers[user] = multiplier
+
+	if m.chatPoints == 0 || !m.live[channel.Name] || now.Sub(m.lastChat[user]) < m.chatCooldown {
+		m.mu.Unlock()
+		return
+	}
+
+	m.lastChat[user] = now
+	amount := m.apply(m.chatPoints, user)
+	m.mu.Unlock()
+
+	if err := m.bot.Service.AddPoints(channel.Name, map[string]int{user.username: amount}); err != nil {
+		logging.WriteError(err)
+	}
+}
+
+// Returns the balance of the user, 0 if they never earned points.
+func (b *TwitchBot) balance(channel *Channel, username string) (int, error) {
+	pts, err := b.Service.GetPoints(channel.Name, username)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return pts.Balance, err
+}
+
+// Parses a points amount, which must be positive unless negative is true.
+func parsePoints(value string, negative bool) (int, error) {
+	amount, err := strconv.Atoi(value)
+	if err != nil || amount == 0 || (amount < 0 && !negative) {
+		return 0, fmt.Errorf("Invalid amount specified: %s", value)
+	}
+	return amount, nil
+}
+
+// Handles the built-in !points command, shows the balance of the user or the specified user.
+//
+// expected format: !points (<user>)
+func (b *TwitchBot) pointsCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	username := message.User.Name
+	displayName := message.User.DisplayName
+
+	if len(args) > 0 && args[0] != "" {
+		displayName = strings.TrimPrefix(args[0], "@")
+		username = strings.ToLower(displayName)
+	}
+
+	balance, err := b.balance(channel, username)
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("%s has %d %s.", displayName, balance, channel.Currency)
+}
+
+// Handles the built-in !give command, moves points of the user to another user.
+//
+// expected format: !give <user> <amount>
+func (b *TwitchBot) giveCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if len(args) < 2 {
+		return "Missing user or amount."
+	}
+
+	target := strings.ToLower(strings.TrimPrefix(args[0], "@"))
+
+	amount, err := parsePoints(args[1], false)
+	if err != nil {
+		return err.Error()
+	}
+
+	if target == message.User.Name {
+		return fmt.Sprintf("You can not give %s to yourself.", channel.Currency)
+	}
+
+	// Prevent typos from eating the points.
+	if _, err := b.Service.GetTwitchUser(channel.Name, target); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Sprintf("%s has not been seen in this chat yet.", target)
+		}
+		return err.Error()
+	}
+
+	if err := b.Service.TransferPoints(channel.Name, message.User.Name, target, amount); err != nil {
+		if err == database.ErrInsufficientPoints {
+			balance, _ := b.balance(channel, message.User.Name)
+			return fmt.Sprintf("You only have %d %s.", balance, channel.Currency)
+		}
+		return err.Error()
+	}
+
+	b.recordEvent(channel.Name, types.PointsGiven, types.PointsEvent{
+		Issuer: message.User.Name,
+		Target: target,
+		Amount: amount,
+	})
+
+	return fmt.Sprintf("You gave %d %s to %s.", amount, channel.Currency, target)
+}
+
+// Handles the built-in !top command, shows the users with the highest balances.
+//
+// expected format: !top
+func (b *TwitchBot) topCommand(channel *Channel) string {
+	top, err := b.Service.GetTopPoints(channel.Name, topPointsLimit)
+	if err != nil {
+		return err.Error()
+	}
+
+	if len(top) == 0 {
+		return fmt.Sprintf("Nobody has any %s yet.", channel.Currency)
+	}
+
+	ranks := []string{}
+	for i, pts := range top {
+		ranks = append(ranks, fmt.Sprintf("%d. %s (%d)", i+1, pts.Username, pts.Balance))
+	}
+
+	return fmt.Sprintf("Top %s: %s", channel.Currency, strings.Join(ranks, ", "))
+}
+
+// Handles the built-in !addpoints command, mods add (or with a negative amount remove) points of a user.
+//
+// expected format: !addpoints <user> <amount>
+func (b *TwitchBot) addPointsCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	if len(args) < 2 {
+		return "Missing user or amount."
+	}
+
+	target := strings.ToLower(strings.TrimPrefix(args[0], "@"))
+
+	amount, err := parsePoints(args[1], true)
+	if err != nil {
+		return err.Error()
+	}
+
+	if err := b.Service.AddPoints(channel.Name, map[string]int{target: amount}); err != nil {
+		return err.Error()
+	}
+
+	b.recordEvent(channel.Name, types.PointsAdded, types.PointsEvent{
+		Issuer: message.User.Name,
+		Target: target,
+		Amount: amount,
+	})
+
+	balance, err := b.balance(channel, target)
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("%s now has %d %s.", target, balance, channel.Currency)
+}
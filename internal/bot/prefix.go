@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Names of the built-in commands, custom commands may not use them.
+var builtinCommands = []string{"commands", "counter", "timers", "quote", "giveaway", "watchtime", "points", "give", "top", "addpoints", "song", "lastsong", "sr", "skip", "srclear", "nowplaying", "songat", "setlist", "title", "settitle", "game", "setgame", "uptime", "followage", "accountage"}
+
+// Sorts the prefixes longest first, so "!!" wins over "!" when both are configured.
+func sortPrefixes(prefixes []string) []string {
+	sorted := append([]string{}, prefixes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+	return sorted
+}
+
+// Removes a configured prefix from the word. Returns false if the word does not start with one.
+func (b *TwitchBot) trimPrefix(word string) (string, bool) {
+	for _, prefix := range b.Prefixes {
+		if strings.HasPrefix(word, prefix) {
+			return strings.TrimPrefix(word, prefix), true
+		}
+	}
+	return word, false
+}
+
+// Returns true if the chat message starts with one of the configured prefixes.
+func (b *TwitchBot) isCommand(text string) bool {
+	_, ok := b.trimPrefix(text)
+	return ok
+}
+
+// Formats a stored command name for chat, using the first configured prefix.
+func (b *TwitchBot) displayCommand(name string) string {
+	return b.Prefixes[0] + name
+}
+
+// Converts a command name given to !commands (with or without prefix) to the name stored on the database.
+func (b *TwitchBot) parseCommandName(word string) (string, error) {
+	name, _ := b.trimPrefix(word)
+
+	if name == "" {
+		return "", fmt.Errorf("missing command name")
+	}
+
+	// Names starting with "!" were stored by older versions with the prefix included.
+	if _, ok := b.trimPrefix(name); ok || strings.HasPrefix(name, "!") {
+		return "", fmt.Errorf("invalid command name: %s", word)
+	}
+
+	for _, builtin := range builtinCommands {
+		if name == builtin {
+			return "", fmt.Errorf("%s is a built-in command", b.displayCommand(name))
+		}
+	}
+
+	return name, nil
+}
@@ -0,0 +1,314 @@
+package bot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+const (
+	// Twitch's chat rate limit window.
+	rateLimitWindow = 30 * time.Second
+	// Messages per window if the bot is not a mod in the target channel.
+	rateLimitUser = 20
+	// Messages per window if the bot is mod or broadcaster in the target channel.
+	rateLimitMod = 100
+
+	// Twitch drops a message if it is identical to the previous one sent within 30 seconds.
+	duplicateWindow = 30 * time.Second
+	// Invisible character appended to duplicate messages so Twitch accepts them.
+	duplicateSuffix = " \U000E0000"
+
+	// Log a warning when this many messages and actions are waiting.
+	queueWarnDepth = 20
+	// Log that the queue recovered once it shrank to this depth after a warning.
+	queueRecoverDepth = 5
+)
+
+type outboundMessage struct {
+	channel string
+	text    string
+	// ID of the message to reply to (reply-parent-msg-id), empty for plain messages.
+	replyTo string
+}
+
+// Helix API call (e.g. a moderation action) waiting to be performed.
+type queuedAction struct {
+	channel string
+	action  func() error
+}
+
+type sentMessage struct {
+	text string
+	sent time.Time
+}
+
+// Outbound chat message queue which respects Twitch's rate limits and duplicate message rule.
+//
+// Every message the bot sends should go through this queue, else Twitch may drop messages or rate limit the bot.
+//
+// Actions are performed by a separate goroutine, so slow Helix requests do not hold back chat messages.
+type messageQueue struct {
+	client *twitch.Client
+	// Returns true if the bot is mod or broadcaster in the specified channel.
+	isMod func(channel string) bool
+
+	mu       sync.Mutex
+	messages []outboundMessage
+	// Timestamps of messages sent within the rate limit window.
+	sent []time.Time
+	// Last message sent per channel, used for the duplicate message rule.
+	last    map[string]sentMessage
+	actions []queuedAction
+	// Whether the queue is above queueWarnDepth since the last warning.
+	congested bool
+
+	wake        chan struct{}
+	actionsWake chan struct{}
+	done        chan struct{}
+	stopped     chan struct{}
+	// Closed once the actions goroutine finished.
+	actionsStopped chan struct{}
+}
+
+// Inits and starts a new message queue.
+func newMessageQueue(client *twitch.Client, isMod func(channel string) bool) *messageQueue {
+	q := messageQueue{}
+
+	q.client = client
+	q.isMod = isMod
+	q.last = make(map[string]sentMessage)
+	q.wake = make(chan struct{}, 1)
+	q.actionsWake = make(chan struct{}, 1)
+	q.done = make(chan struct{})
+	q.stopped = make(chan struct{})
+	q.actionsStopped = make(chan struct{})
+
+	go q.run()
+	go q.runActions()
+
+	return &q
+}
+
+// Adds a message to the queue. Messages are sent in order.
+func (q *messageQueue) Enqueue(channel, text string) {
+	q.push(outboundMessage{channel: channel, text: text})
+}
+
+// Adds a reply to the specified message to the queue.
+func (q *messageQueue) EnqueueReply(channel, parentID, text string) {
+	q.push(outboundMessage{channel: channel, text: text, replyTo: parentID})
+}
+
+func (q *messageQueue) push(msg outboundMessage) {
+	q.mu.Lock()
+	q.messages = append(q.messages, msg)
+	report := q.checkDepth()
+	q.mu.Unlock()
+
+	q.reportDepth(report)
+	wakeUp(q.wake)
+}
+
+// Adds an action (e.g. a Helix moderation request) to the queue.
+//
+// Actions are performed in order, apart from chat messages, so they are never held back by the chat rate limit.
+func (q *messageQueue) EnqueueAction(channel string, action func() error) {
+	q.mu.Lock()
+	q.actions = append(q.actions, queuedAction{channel: channel, action: action})
+	report := q.checkDepth()
+	q.mu.Unlock()
+
+	q.reportDepth(report)
+	wakeUp(q.actionsWake)
+}
+
+// Tracks whether the queue got congested or recovered and returns what to log, empty if nothing changed. Lock needs to be held.
+//
+// Warns once when the depth reaches queueWarnDepth and not again until it went down to queueRecoverDepth.
+func (q *messageQueue) checkDepth() depthReport {
+	depth := len(q.messages) + len(q.actions)
+
+	switch {
+	case !q.congested && depth >= queueWarnDepth:
+		q.congested = true
+		return depthReport{depth: depth, congested: true}
+	case q.congested && depth <= queueRecoverDepth:
+		q.congested = false
+		return depthReport{depth: depth, recovered: true}
+	default:
+		return depthReport{}
+	}
+}
+
+// Change of the queue's congestion, see checkDepth().
+type depthReport struct {
+	depth     int
+	congested bool
+	recovered bool
+}
+
+// Logs a change of the queue's congestion. Call it without holding the lock.
+func (q *messageQueue) reportDepth(report depthReport) {
+	switch {
+	case report.congested:
+		logging.WriteWarn(fmt.Sprintf("Outbound message queue is getting long (%d messages and actions)", report.depth))
+	case report.recovered:
+		logging.WriteInfo(fmt.Sprintf("Outbound message queue is back to normal (%d messages and actions)", report.depth))
+	}
+}
+
+// Wakes up a goroutine without blocking if it is already awake.
+func wakeUp(wake chan struct{}) {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// Returns how many messages and actions are waiting to be sent.
+func (q *messageQueue) Depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.messages) + len(q.actions)
+}
+
+// Stops the queue. Messages which have not been sent yet will be dropped.
+//
+// Waits for an action which is being performed right now.
+func (q *messageQueue) Stop() {
+	close(q.done)
+	<-q.stopped
+	<-q.actionsStopped
+
+	if depth := q.Depth(); depth > 0 {
+		logging.WriteWarn(fmt.Sprintf("Dropped %d unsent message(s) on shutdown", depth))
+	}
+}
+
+// Sends queued messages as soon as the rate limit allows it.
+func (q *messageQueue) run() {
+	defer close(q.stopped)
+
+	for {
+		msg, wait, ok := q.next()
+
+		if !ok {
+			select {
+			case <-q.wake:
+				continue
+			case <-q.done:
+				return
+			}
+		}
+
+		if wait > 0 {
+			select {
+			case <-time.After(wait):
+				continue
+			case <-q.done:
+				return
+			}
+		}
+
+		if msg.replyTo != "" {
+			q.client.Reply(msg.channel, msg.replyTo, msg.text)
+		} else {
+			q.client.Say(msg.channel, msg.text)
+		}
+
+		q.mu.Lock()
+		report := q.checkDepth()
+		q.mu.Unlock()
+
+		q.reportDepth(report)
+
+		select {
+		case <-q.done:
+			return
+		default:
+		}
+	}
+}
+
+// Performs queued actions one after another.
+func (q *messageQueue) runActions() {
+	defer close(q.actionsStopped)
+
+	for {
+		q.mu.Lock()
+		if len(q.actions) == 0 {
+			q.mu.Unlock()
+
+			select {
+			case <-q.actionsWake:
+				continue
+			case <-q.done:
+				return
+			}
+		}
+		action := q.actions[0]
+		q.actions = q.actions[1:]
+		report := q.checkDepth()
+		q.mu.Unlock()
+
+		q.reportDepth(report)
+
+		if err := action.action(); err != nil {
+			logging.WriteError(fmt.Errorf("action in %s failed: %w", action.channel, err))
+		}
+
+		select {
+		case <-q.done:
+			return
+		default:
+		}
+	}
+}
+
+// Returns the next message if the rate limit allows sending it, else how long to wait.
+//
+// Returns false if the queue is empty.
+func (q *messageQueue) next() (outboundMessage, time.Duration, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.messages) == 0 {
+		return outboundMessage{}, 0, false
+	}
+
+	msg := q.messages[0]
+
+	now := time.Now()
+	isMod := q.isMod(msg.channel)
+
+	// Forget messages which left the rate limit window.
+	for len(q.sent) > 0 && now.Sub(q.sent[0]) >= rateLimitWindow {
+		q.sent = q.sent[1:]
+	}
+
+	limit := rateLimitUser
+	if isMod {
+		limit = rateLimitMod
+	}
+
+	if len(q.sent) >= limit {
+		return msg, q.sent[len(q.sent)-limit].Add(rateLimitWindow).Sub(now), true
+	}
+
+	q.messages = q.messages[1:]
+
+	// Mods may send duplicate messages, anyone else needs to alter the message.
+	last, ok := q.last[msg.channel]
+	if !isMod && ok && last.text == msg.text && now.Sub(last.sent) < duplicateWindow {
+		msg.text += duplicateSuffix
+	}
+
+	q.sent = append(q.sent, now)
+	q.last[msg.channel] = sentMessage{text: msg.text, sent: now}
+
+	return msg, 0, true
+}
@@ -0,0 +1,240 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/helix"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Maximum of quote numbers listed by !quote search.
+const quoteSearchLimit = 10
+
+// Handles the built-in !quote command, args are the words after "!quote".
+//
+// expected format:
+//
+//	!quote (<number>)
+//	!quote add (@<user>) <text>, or as reply to the message to quote
+//	!quote search <term>
+//	!quote delete <number>
+func (b *TwitchBot) quoteCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if len(args) == 0 {
+		quote, err := b.Service.GetRandomQuote(channel.Name)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return "No quotes on database yet."
+			}
+			return err.Error()
+		}
+
+		return formatQuote(quote)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		if !channel.HasUserLevel(message, b.QuoteAddLevel) {
+			return "You are not allowed to use that command."
+		}
+
+		return b.addQuote(channel, message, args[1:])
+
+	case "search":
+		if len(args) < 2 {
+			return "Missing search term."
+		}
+
+		term := strings.Join(args[1:], " ")
+
+		quotes, err := b.Service.SearchQuotes(channel.Name, term)
+		if err != nil {
+			return err.Error()
+		}
+
+		switch len(quotes) {
+		case 0:
+			return fmt.Sprintf("No quotes matching \"%s\".", term)
+		case 1:
+			return formatQuote(quotes[0])
+		}
+
+		numbers := []string{}
+		for i, quote := range quotes {
+			if i == quoteSearchLimit {
+				numbers = append(numbers, fmt.Sprintf("and %d more", len(quotes)-i))
+				break
+			}
+			numbers = append(numbers, fmt.Sprintf("#%d", quote.Number))
+		}
+
+		return fmt.Sprintf("Quotes matching \"%s\": %s", term, strings.Join(numbers, ", "))
+
+	case "delete":
+		if !channel.HasUserLevel(message, types.Moderator) {
+			return "You are not allowed to use that command."
+		}
+
+		if len(args) < 2 {
+			return "Missing quote number."
+		}
+
+		number, err := parseQuoteNumber(args[1])
+		if err != nil {
+			return err.Error()
+		}
+
+		// Load the quote first so the event still holds its text.
+		quote, err := b.Service.GetQuote(channel.Name, number)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Sprintf("Quote #%d does not exist.", number)
+			}
+			return err.Error()
+		}
+
+		if err := b.Service.DeleteQuote(channel.Name, number); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Sprintf("Quote #%d does not exist.", number)
+			}
+			return err.Error()
+		}
+
+		b.recordEvent(channel.Name, types.QuoteDeleted, types.QuoteEvent{
+			Issuer:     message.User.Name,
+			Number:     quote.Number,
+			Text:       quote.Text,
+			QuotedUser: quote.QuotedUser,
+		})
+
+		return fmt.Sprintf("Quote #%d has successfully been deleted.", number)
+
+	default:
+		number, err := parseQuoteNumber(args[0])
+		if err != nil {
+			return fmt.Sprintf("Invalid subcommand: %s", args[0])
+		}
+
+		quote, err := b.Service.GetQuote(channel.Name, number)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Sprintf("Quote #%d does not exist.", number)
+			}
+			return err.Error()
+		}
+
+		return formatQuote(quote)
+	}
+}
+
+// Adds a quote from the words after "!quote add".
+//
+// If the command is a reply, the replied to message is quoted unless a text was given.
+// Otherwise the quote is attributed to "@user" in front of the text, or the channel owner.
+func (b *TwitchBot) addQuote(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	quote := database.Quote{}
+	quote.Channel = channel.Name
+	quote.Author = message.User.Name
+	quote.QuotedUser = channel.Owner
+	quote.Added = time.Now()
+
+	if message.Reply != nil {
+		quote.QuotedUser = message.Reply.ParentDisplayName
+		quote.Text = message.Reply.ParentMsgBody
+	}
+
+	if len(args) > 1 && strings.HasPrefix(args[0], "@") {
+		quote.QuotedUser = strings.TrimPrefix(args[0], "@")
+		args = args[1:]
+	}
+
+	if len(args) > 0 {
+		quote.Text = strings.Join(args, " ")
+	}
+
+	if quote.Text == "" {
+		return "Missing quote text."
+	}
+
+	// The quote is still added if Twitch can not be reached, just without game and stream date.
+	if err := b.addStreamDetails(channel, &quote); err != nil {
+		logging.WriteError(err)
+	}
+
+	added, err := b.Service.AddQuote(quote)
+	// Somebody else added a quote at the same time and took the number, try once more.
+	if err == sql.ErrTxDone {
+		added, err = b.Service.AddQuote(quote)
+	}
+	if err != nil {
+		return err.Error()
+	}
+
+	b.recordEvent(channel.Name, types.QuoteAdded, types.QuoteEvent{
+		Issuer:     message.User.Name,
+		Number:     added.Number,
+		Text:       added.Text,
+		QuotedUser: added.QuotedUser,
+	})
+
+	return fmt.Sprintf("Quote #%d has successfully been added.", added.Number)
+}
+
+// Sets the game and, if the channel is live, the stream date of a quote.
+func (b *TwitchBot) addStreamDetails(channel *Channel, quote *database.Quote) error {
+	if channel.ID() == "" {
+		return fmt.Errorf("channel id of %s is not known yet", channel.Name)
+	}
+
+	stream, err := b.Helix.GetStream(channel.ID())
+	if err == nil {
+		quote.Game = stream.GameName
+		quote.StreamDate = sql.NullTime{Time: stream.StartedAt, Valid: true}
+		return nil
+	}
+	if !errors.Is(err, helix.ErrNotFound) {
+		return err
+	}
+
+	// Offline, the channel information still holds the last game.
+	info, err := b.Helix.GetChannelInformation(channel.ID())
+	if err != nil {
+		return err
+	}
+
+	quote.Game = info.GameName
+
+	return nil
+}
+
+// Parses a quote number like "12" or "#12".
+func parseQuoteNumber(value string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(value, "#"))
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("Invalid quote number specified: %s", value)
+	}
+
+	return number, nil
+}
+
+// Formats a quote like: #12: "text" - user (game, 2006-01-02)
+func formatQuote(quote database.Quote) string {
+	date := quote.Added
+	if quote.StreamDate.Valid {
+		date = quote.StreamDate.Time
+	}
+
+	details := date.Format("2006-01-02")
+	if quote.Game != "" {
+		details = quote.Game + ", " + details
+	}
+
+	return fmt.Sprintf("#%d: \"%s\" - %s (%s)", quote.Number, quote.Text, quote.QuotedUser, details)
+}
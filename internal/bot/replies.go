@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Kind of answer the bot sends to a chat message, every kind can be configured to reply or mention.
+type responseType int
+
+const (
+	ResponseCommand responseType = iota
+	ResponseGateKeeper
+)
+
+const (
+	// Threads the answer to the message via reply-parent-msg-id.
+	ResponseModeReply = "reply"
+	// Sends the answer as normal message starting with "@user".
+	ResponseModeMention = "mention"
+)
+
+// Answers a chat message using the configured response mode of the response type.
+func (b *TwitchBot) Respond(responseType responseType, message twitch.PrivateMessage, text string) {
+	if b.ResponseModes[responseType] == ResponseModeReply && message.ID != "" {
+		b.queue.EnqueueReply(message.Channel, message.ID, text)
+		return
+	}
+
+	b.SendMessage(message.Channel, fmt.Sprintf("@%s => %s", message.User.DisplayName, text))
+}
+
+// Removes the "@user " mention Twitch puts in front of replies, so "@user !command" is handled like "!command".
+//
+// The replied to message stays available via message.Reply.
+func stripReplyMention(message twitch.PrivateMessage) twitch.PrivateMessage {
+	if message.Reply == nil {
+		return message
+	}
+
+	mention := "@" + message.Reply.ParentUserLogin
+	if len(message.Message) >= len(mention) && strings.EqualFold(message.Message[:len(mention)], mention) {
+		message.Message = strings.TrimLeft(message.Message[len(mention):], " ")
+	}
+
+	return message
+}
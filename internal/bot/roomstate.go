@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Returns the current room state (slow, sub-only, emote-only, followers-only, ...) of the channel.
+//
+// Safe to use from any goroutine.
+func (c *Channel) RoomState() types.RoomState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.roomState
+}
+
+// Applies a ROOMSTATE message to the in-memory room state.
+//
+// Stores the new room state on the database if anything changed.
+func (b *TwitchBot) HandleRoomState(message twitch.RoomStateMessage) error {
+	channel, ok := b.GetChannel(message.Channel)
+	if !ok {
+		return fmt.Errorf("got room state for unknown channel %s", message.Channel)
+	}
+
+	channel.setID(message.RoomID)
+
+	channel.mu.Lock()
+	oldState := channel.roomState
+	newState := oldState.Apply(message.State)
+	channel.roomState = newState
+	channel.mu.Unlock()
+
+	if newState == oldState {
+		return nil
+	}
+
+	_, err := b.Service.AddRoomStateEvent(database.RoomStateEvent{
+		Channel:       channel.Name,
+		EmoteOnly:     newState.EmoteOnly,
+		FollowersOnly: newState.FollowersOnly,
+		R9K:           newState.R9K,
+		Slow:          newState.Slow,
+		SubsOnly:      newState.SubsOnly,
+		Changed:       time.Now(),
+	})
+
+	return err
+}
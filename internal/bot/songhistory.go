@@ -0,0 +1,170 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Directory setlists are exported to.
+const setlistDir = "./files/setlists"
+
+// Returns the poller if the song history belongs to the channel, otherwise the answer for chat.
+func (b *TwitchBot) songHistoryIn(channel *Channel) (*nowPlayingPoller, string) {
+	if b.nowPlaying == nil {
+		return nil, "The song history is disabled."
+	}
+
+	if b.nowPlaying.channel != channel.Name {
+		return nil, "The song history is not available in this channel."
+	}
+
+	return b.nowPlaying, ""
+}
+
+// Returns the start of the current stream, or of the last stream with songs if the channel is offline.
+func (b *TwitchBot) historyStreamStart(channel *Channel) (time.Time, error) {
+	started, err := b.streamStart(channel)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if started.Valid {
+		return started.Time, nil
+	}
+
+	return b.Service.GetLastStreamStart(channel.Name)
+}
+
+// Handles the built-in !songat command, shows the track which was playing at a time of the stream.
+//
+// Uses the current stream, or the last one if the channel is offline.
+//
+// expected format: !songat <h:mm | h:mm:ss | duration like 1h23m>
+func (b *TwitchBot) songAtCommand(channel *Channel, args []string) string {
+	if _, answer := b.songHistoryIn(channel); answer != "" {
+		return answer
+	}
+
+	if len(args) == 0 {
+		return "Please specify a time of the stream, e.g. 1:23."
+	}
+
+	offset, err := parseStreamOffset(args[0])
+	if err != nil {
+		return fmt.Sprintf("Invalid time specified: %s", args[0])
+	}
+
+	started, err := b.historyStreamStart(channel)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "No songs have been played on stream yet."
+	}
+	if err != nil {
+		return err.Error()
+	}
+
+	song, err := b.Service.GetSongAt(channel.Name, started, started.Add(offset))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Sprintf("No song was playing at %s.", formatStreamOffset(offset))
+	}
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("At %s: %s by %s %s", formatStreamOffset(offset), song.Title, song.Artists, song.URL)
+}
+
+// Handles the built-in !setlist command, mods export the songs of the current or last stream to a file.
+//
+// expected format: !setlist
+func (b *TwitchBot) setlistCommand(channel *Channel, message twitch.PrivateMessage) string {
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	if _, answer := b.songHistoryIn(channel); answer != "" {
+		return answer
+	}
+
+	started, err := b.historyStreamStart(channel)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "No songs have been played on stream yet."
+	}
+	if err != nil {
+		return err.Error()
+	}
+
+	songs, err := b.Service.GetStreamSongs(channel.Name, started)
+	if err != nil {
+		return err.Error()
+	}
+
+	if len(songs) == 0 {
+		return "No songs have been played on stream yet."
+	}
+
+	var setlist strings.Builder
+	for _, song := range songs {
+		// Tracks started before the stream count from its start.
+		offset := song.Started.Sub(started)
+		if offset < 0 {
+			offset = 0
+		}
+		fmt.Fprintf(&setlist, "%s %s by %s %s\n", formatStreamOffset(offset), song.Title, song.Artists, song.URL)
+	}
+
+	if err := os.MkdirAll(setlistDir, os.ModePerm); err != nil {
+		return err.Error()
+	}
+
+	path := filepath.Join(setlistDir, fmt.Sprintf("%s_%s.txt", channel.Name, started.Format("2006-01-02_15-04")))
+
+	if err := os.WriteFile(path, []byte(setlist.String()), 0o644); err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("Exported %d songs to %s.", len(songs), path)
+}
+
+// Parses a time of the stream like 1:23 (hours and minutes), 1:23:45 or a duration like 1h23m.
+func parseStreamOffset(value string) (time.Duration, error) {
+	if !strings.Contains(value, ":") {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid stream time: %s", value)
+		}
+		return d, nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid stream time: %s", value)
+	}
+
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	offset := time.Duration(0)
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid stream time: %s", value)
+		}
+		offset += time.Duration(n) * units[i]
+	}
+
+	return offset, nil
+}
+
+// Formats a time of the stream like 1:23:45.
+func formatStreamOffset(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
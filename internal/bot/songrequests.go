@@ -0,0 +1,345 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/config"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/devusSs/twitch-kraken/internal/spotify"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+const (
+	// How often the song request manager checks if the next request should be queued.
+	songRequestTick = 5 * time.Second
+	// Requests are added to the Spotify queue this long before the current track ends,
+	// so !srclear can still remove them until then. Needs to cover the Spotify client's cache.
+	songRequestLead = 20 * time.Second
+)
+
+// Keeps the song requests in the bot's own queue and adds them to the Spotify queue one at a time.
+//
+// Spotify does not allow removing tracks from its queue, this way pending requests can still be cleared.
+type songRequestManager struct {
+	bot *TwitchBot
+	// The only channel which may request songs, the Spotify account belongs to its streamer.
+	channel        string
+	maxPerUser     int
+	maxLength      time.Duration
+	skipVotes      int
+	blockedTracks  map[string]bool
+	blockedArtists map[string]bool
+
+	mu sync.Mutex
+	// Track the last request was added to the Spotify queue during, empty if nothing was playing.
+	pushedFor string
+	pushed    bool
+	// Track the skip votes are for and who voted.
+	votesFor string
+	votes    map[string]bool
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// Inits and starts a new song request manager.
+func newSongRequestManager(bot *TwitchBot, cfg *config.Config) *songRequestManager {
+	m := songRequestManager{}
+
+	m.bot = bot
+	m.channel = cfg.SongRequests.Channel
+	m.maxPerUser = cfg.SongRequests.MaxPerUser
+	m.maxLength = time.Duration(cfg.SongRequests.MaxLength) * time.Second
+	m.skipVotes = cfg.SongRequests.SkipVotes
+	m.blockedTracks = make(map[string]bool)
+	m.blockedArtists = make(map[string]bool)
+	m.votes = make(map[string]bool)
+	m.done = make(chan struct{})
+	m.stopped = make(chan struct{})
+
+	for _, track := range cfg.SongRequests.BlockedTracks {
+		if id, ok := spotify.ParseTrackID(track); ok {
+			track = id
+		}
+		m.blockedTracks[track] = true
+	}
+
+	// Artists may be blocked by ID or by name.
+	for _, artist := range cfg.SongRequests.BlockedArtists {
+		m.blockedArtists[strings.ToLower(artist)] = true
+	}
+
+	go m.run()
+
+	return &m
+}
+
+// Stops the manager and waits for it to finish.
+func (m *songRequestManager) Stop() {
+	close(m.done)
+	<-m.stopped
+}
+
+func (m *songRequestManager) run() {
+	defer close(m.stopped)
+
+	ticker := time.NewTicker(songRequestTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.tick(); err != nil {
+				logging.WriteError(err)
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// Adds the oldest pending request to the Spotify queue once the current track is about to end.
+func (m *songRequestManager) tick() error {
+	pending, err := m.bot.Service.GetPendingSongRequests(m.channel)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	current := ""
+	playing, err := m.bot.Spotify.CurrentlyPlaying()
+	switch {
+	case err == nil:
+		current = playing.Track.ID
+		remaining := playing.Track.Duration() - playing.Progress - time.Since(playing.RetrievedAt)
+		if remaining > songRequestLead {
+			return nil
+		}
+	case errors.Is(err, spotify.ErrAdPlaying):
+		return nil
+	case !errors.Is(err, spotify.ErrNothingPlaying):
+		return err
+	}
+
+	// Only one request per track, the next one follows at the end of the requested track.
+	m.mu.Lock()
+	if m.pushed && m.pushedFor == current {
+		m.mu.Unlock()
+		return nil
+	}
+	m.mu.Unlock()
+
+	request := pending[0]
+
+	if err := m.bot.Spotify.AddToQueue(request.TrackURI); err != nil {
+		return fmt.Errorf("could not add song request %d to the Spotify queue: %w", request.ID, err)
+	}
+
+	m.mu.Lock()
+	m.pushedFor = current
+	m.pushed = true
+	m.mu.Unlock()
+
+	return m.bot.Service.MarkSongRequestQueued(request.ID, time.Now())
+}
+
+// Returns why the track may not be requested, empty if it may.
+func (m *songRequestManager) rejection(track spotify.Track) string {
+	if m.blockedTracks[track.ID] {
+		return fmt.Sprintf("%s can not be requested.", track)
+	}
+
+	for _, artist := range track.Artists {
+		if m.blockedArtists[artist.ID] || m.blockedArtists[strings.ToLower(artist.Name)] {
+			return fmt.Sprintf("Songs by %s can not be requested.", artist.Name)
+		}
+	}
+
+	if m.maxLength > 0 && track.Duration() > m.maxLength {
+		return fmt.Sprintf("%s is too long, songs may be %s at most.", track, formatTrackLength(m.maxLength))
+	}
+
+	return ""
+}
+
+// Adds a vote to skip the track, returns the votes and whether the votes are enough.
+//
+// Votes are reset once the track changes.
+func (m *songRequestManager) vote(trackID, username string) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.votesFor != trackID {
+		m.votesFor = trackID
+		m.votes = make(map[string]bool)
+	}
+
+	m.votes[username] = true
+
+	if len(m.votes) >= m.skipVotes {
+		m.votes = make(map[string]bool)
+		return m.skipVotes, true
+	}
+
+	return len(m.votes), false
+}
+
+// Formats a track length like 3:05.
+func formatTrackLength(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// Returns the song request manager if song requests are enabled in the channel, otherwise the answer for chat.
+func (b *TwitchBot) songRequestsIn(channel *Channel) (*songRequestManager, string) {
+	if b.songRequests == nil {
+		return nil, "Song requests are disabled."
+	}
+
+	if b.songRequests.channel != channel.Name {
+		return nil, "Song requests are not available in this channel."
+	}
+
+	return b.songRequests, ""
+}
+
+// Handles the built-in !sr command, requests a track by Spotify link or search.
+//
+// expected format: !sr <spotify link | search>
+func (b *TwitchBot) songRequestCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	m, answer := b.songRequestsIn(channel)
+	if m == nil {
+		return answer
+	}
+
+	search := strings.TrimSpace(strings.Join(args, " "))
+	if search == "" {
+		return "Missing Spotify link or search."
+	}
+
+	var track spotify.Track
+	var err error
+
+	if id, ok := spotify.ParseTrackID(search); ok {
+		track, err = b.Spotify.GetTrack(id)
+	} else {
+		var tracks []spotify.Track
+		tracks, err = b.Spotify.SearchTracks(search, 1)
+		if err == nil {
+			track = tracks[0]
+		}
+	}
+
+	if errors.Is(err, spotify.ErrNotFound) {
+		return fmt.Sprintf("No song found for \"%s\".", search)
+	}
+	if err != nil {
+		return spotifyErrorMessage(err)
+	}
+
+	if reason := m.rejection(track); reason != "" {
+		return reason
+	}
+
+	pending, err := b.Service.GetPendingSongRequests(channel.Name)
+	if err != nil {
+		return err.Error()
+	}
+
+	byUser := 0
+	for _, request := range pending {
+		if request.TrackID == track.ID {
+			return fmt.Sprintf("%s has already been requested.", track)
+		}
+		if request.Username == message.User.Name {
+			byUser++
+		}
+	}
+
+	if byUser >= m.maxPerUser && !channel.HasUserLevel(message, types.Moderator) {
+		return fmt.Sprintf("You already have %d songs waiting in the queue.", byUser)
+	}
+
+	request := database.SongRequest{}
+	request.Channel = channel.Name
+	request.Username = message.User.Name
+	request.TrackID = track.ID
+	request.TrackURI = track.URI
+	request.Title = track.Name
+	request.Artists = track.ArtistNames()
+	request.DurationMS = track.DurationMS
+	request.Requested = time.Now()
+
+	if _, err := b.Service.AddSongRequest(request); err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("Added %s to the queue (#%d).", track, len(pending)+1)
+}
+
+// Handles the built-in !skip command, mods skip right away, everyone else votes.
+//
+// expected format: !skip
+func (b *TwitchBot) skipCommand(channel *Channel, message twitch.PrivateMessage) string {
+	m, answer := b.songRequestsIn(channel)
+	if m == nil {
+		return answer
+	}
+
+	playing, err := b.Spotify.CurrentlyPlaying()
+	if err != nil {
+		return spotifyErrorMessage(err)
+	}
+
+	if !channel.HasUserLevel(message, types.Moderator) {
+		votes, skip := m.vote(playing.Track.ID, message.User.Name)
+		if !skip {
+			return fmt.Sprintf("Vote to skip %s added (%d/%d).", playing.Track, votes, m.skipVotes)
+		}
+	}
+
+	if err := b.Spotify.SkipToNext(); err != nil {
+		return spotifyErrorMessage(err)
+	}
+
+	if err := b.Service.MarkSongRequestSkipped(channel.Name, playing.Track.ID); err != nil {
+		logging.WriteError(err)
+	}
+
+	b.recordEvent(channel.Name, types.SongSkipped, types.SongEvent{
+		Issuer: message.User.Name,
+		Track:  playing.Track.String(),
+	})
+
+	return fmt.Sprintf("Skipped %s.", playing.Track)
+}
+
+// Handles the built-in !srclear command, mods remove every request which is not in the Spotify queue yet.
+//
+// expected format: !srclear
+func (b *TwitchBot) clearSongRequestsCommand(channel *Channel, message twitch.PrivateMessage) string {
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	if _, answer := b.songRequestsIn(channel); answer != "" {
+		return answer
+	}
+
+	cleared, err := b.Service.ClearSongRequests(channel.Name)
+	if err != nil {
+		return err.Error()
+	}
+
+	b.recordEvent(channel.Name, types.SongRequestsCleared, types.SongEvent{
+		Issuer:  message.User.Name,
+		Cleared: cleared,
+	})
+
+	return fmt.Sprintf("Cleared %d song requests.", cleared)
+}
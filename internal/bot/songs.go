@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/devusSs/twitch-kraken/internal/spotify"
+)
+
+// Returns why the Spotify account can not be shown in the channel, empty if it can.
+func (b *TwitchBot) spotifyUnavailable(channel *Channel) string {
+	if channel.Name != b.SpotifyChannel {
+		return fmt.Sprintf("Songs are only available in %s.", b.SpotifyChannel)
+	}
+	return ""
+}
+
+// Handles the built-in !song command, shows the track playing on Spotify right now.
+//
+// expected format: !song
+func (b *TwitchBot) songCommand(channel *Channel) string {
+	if answer := b.spotifyUnavailable(channel); answer != "" {
+		return answer
+	}
+
+	playing, err := b.Spotify.CurrentlyPlaying()
+	if err != nil {
+		return spotifyErrorMessage(err)
+	}
+
+	return fmt.Sprintf("Now playing: %s %s", playing.Track, playing.Track.ExternalURLs.Spotify)
+}
+
+// Handles the built-in !lastsong command, shows the track played before the current one.
+//
+// expected format: !lastsong
+func (b *TwitchBot) lastSongCommand(channel *Channel) string {
+	if answer := b.spotifyUnavailable(channel); answer != "" {
+		return answer
+	}
+
+	history, err := b.Spotify.RecentlyPlayed(5)
+	if err != nil {
+		return spotifyErrorMessage(err)
+	}
+
+	// The history may already hold the current track if it is played again.
+	current := ""
+	if playing, err := b.Spotify.CurrentlyPlaying(); err == nil {
+		current = playing.Track.ID
+	}
+
+	for _, played := range history {
+		if played.Track.ID == current {
+			continue
+		}
+
+		return fmt.Sprintf("Last song: %s %s", played.Track, played.Track.ExternalURLs.Spotify)
+	}
+
+	return "No song has been played yet."
+}
+
+// Converts Spotify errors to chat answers.
+func spotifyErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, spotify.ErrNothingPlaying):
+		return "Nothing is playing right now."
+	case errors.Is(err, spotify.ErrAdPlaying):
+		return "An ad is playing right now, the music will be back soon."
+	default:
+		return fmt.Sprintf("Could not reach Spotify: %s", err.Error())
+	}
+}
@@ -0,0 +1,296 @@
+// Variables for custom command outputs, e.g. "Hello ${user}, you rolled ${random 1-100}!".
+//
+// Write "$${" to output a literal "${".
+package template
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+	// Time zones for ${time}, the host may not have them installed.
+	_ "time/tzdata"
+
+	"github.com/devusSs/twitch-kraken/internal/utils"
+)
+
+// Data a command output is rendered with. Functions are only called if the output uses their variable.
+type Context struct {
+	User    string   // display name of the user who called the command
+	Args    []string // words after the command name
+	Channel string
+
+	// Returns a random user who chatted recently.
+	RandomUser func() (string, error)
+	// Returns how often the command has been used (empty name) or the value of a named counter.
+	Count func(name string) (int, error)
+	// Returns how long the stream has been live, ErrOffline if it is not.
+	Uptime func() (time.Duration, error)
+}
+
+// Returned by Context.Uptime if the stream is offline.
+var ErrOffline = errors.New("stream is offline")
+
+// Single ${name arg} placeholder.
+type variable struct {
+	name string
+	arg  string
+}
+
+// Part of a parsed template, either literal text or a variable.
+type segment struct {
+	text     string
+	variable *variable
+}
+
+// Checks a command output for unknown variables or invalid arguments.
+//
+// Use it when a command is added or edited, so mistakes are reported right away instead of on every call.
+func Validate(text string) error {
+	segments, err := parse(text)
+	if err != nil {
+		return err
+	}
+
+	for _, segment := range segments {
+		if segment.variable == nil {
+			continue
+		}
+
+		if err := validateVariable(*segment.variable); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Replaces the variables of a command output with their values.
+//
+// Values are inserted as plain text, variables inside of values will not be expanded.
+func Render(text string, ctx Context) (string, error) {
+	segments, err := parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+
+	for _, segment := range segments {
+		if segment.variable == nil {
+			out.WriteString(segment.text)
+			continue
+		}
+
+		value, err := renderVariable(*segment.variable, ctx)
+		if err != nil {
+			return "", err
+		}
+
+		out.WriteString(value)
+	}
+
+	rendered := out.String()
+
+	// Values must not turn the output into a chat command like "/ban" or ".ban".
+	if !strings.HasPrefix(text, "/") && !strings.HasPrefix(text, ".") {
+		rendered = strings.TrimLeft(rendered, "/. ")
+	}
+
+	return rendered, nil
+}
+
+// Splits a template into literal text and variables.
+func parse(text string) ([]segment, error) {
+	segments := []segment{}
+	var literal strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		if strings.HasPrefix(text[i:], "$${") {
+			literal.WriteString("${")
+			i += 2
+			continue
+		}
+
+		if !strings.HasPrefix(text[i:], "${") {
+			literal.WriteByte(text[i])
+			continue
+		}
+
+		end := strings.IndexByte(text[i:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("missing \"}\" for variable at position %d", i+1)
+		}
+
+		content := strings.TrimSpace(text[i+2 : i+end])
+		if content == "" {
+			return nil, fmt.Errorf("empty variable at position %d", i+1)
+		}
+
+		name, arg, _ := strings.Cut(content, " ")
+
+		if literal.Len() > 0 {
+			segments = append(segments, segment{text: literal.String()})
+			literal.Reset()
+		}
+
+		segments = append(segments, segment{variable: &variable{strings.ToLower(name), strings.TrimSpace(arg)}})
+
+		i += end
+	}
+
+	if literal.Len() > 0 {
+		segments = append(segments, segment{text: literal.String()})
+	}
+
+	return segments, nil
+}
+
+// Checks if the variable is known and its argument is valid.
+func validateVariable(v variable) error {
+	switch v.name {
+	case "user", "touser", "args", "randomuser", "uptime", "channel":
+		if v.arg != "" {
+			return fmt.Errorf("variable ${%s} does not take an argument", v.name)
+		}
+		return nil
+
+	case "count":
+		if strings.Contains(v.arg, " ") {
+			return fmt.Errorf("invalid counter name \"%s\" for ${count}, names may not contain spaces", v.arg)
+		}
+		return nil
+
+	case "random":
+		_, _, err := parseRange(v.arg)
+		return err
+
+	case "time":
+		_, err := parseLocation(v.arg)
+		return err
+
+	default:
+		return fmt.Errorf("unknown variable ${%s}", v.name)
+	}
+}
+
+// Returns the value of a variable.
+func renderVariable(v variable, ctx Context) (string, error) {
+	if err := validateVariable(v); err != nil {
+		return "", err
+	}
+
+	switch v.name {
+	case "user":
+		return ctx.User, nil
+
+	case "touser":
+		// Fall back to the calling user, so "!hug" without a target still makes sense.
+		if len(ctx.Args) == 0 {
+			return ctx.User, nil
+		}
+		return strings.TrimPrefix(ctx.Args[0], "@"), nil
+
+	case "args":
+		return strings.Join(ctx.Args, " "), nil
+
+	case "channel":
+		return ctx.Channel, nil
+
+	case "random":
+		// Outputs stored before a range was validated may still hold an invalid one.
+		min, max, err := parseRange(v.arg)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(min+rand.Int63n(max-min+1), 10), nil
+
+	case "randomuser":
+		if ctx.RandomUser == nil {
+			return "", fmt.Errorf("variable ${%s} is not available", v.name)
+		}
+		return ctx.RandomUser()
+
+	case "count":
+		if ctx.Count == nil {
+			return "", fmt.Errorf("variable ${%s} is not available", v.name)
+		}
+		count, err := ctx.Count(strings.ToLower(v.arg))
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(count), nil
+
+	case "uptime":
+		if ctx.Uptime == nil {
+			return "", fmt.Errorf("variable ${%s} is not available", v.name)
+		}
+		uptime, err := ctx.Uptime()
+		if errors.Is(err, ErrOffline) {
+			return "offline", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return utils.FormatDuration(uptime), nil
+
+	case "time":
+		location, _ := parseLocation(v.arg)
+		return time.Now().In(location).Format("15:04 MST"), nil
+
+	default:
+		return "", fmt.Errorf("unknown variable ${%s}", v.name)
+	}
+}
+
+// Bounds of ${random} ranges, so max-min+1 can never overflow.
+const maxRandomBound = 1_000_000_000
+
+// Parses the "min-max" argument of ${random}, defaults to 1-100. Both bounds may be negative, e.g. -5-5.
+func parseRange(arg string) (int64, int64, error) {
+	if arg == "" {
+		return 1, 100, nil
+	}
+
+	invalid := fmt.Errorf("invalid range \"%s\" for ${random}, expected e.g. 1-100", arg)
+
+	// Skip the sign of min when looking for the separator.
+	arg = strings.TrimSpace(arg)
+	sep := strings.Index(strings.TrimPrefix(arg, "-"), "-")
+	if sep < 0 {
+		return 0, 0, invalid
+	}
+	sep += len(arg) - len(strings.TrimPrefix(arg, "-"))
+
+	min, err := strconv.ParseInt(strings.TrimSpace(arg[:sep]), 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+
+	max, err := strconv.ParseInt(strings.TrimSpace(arg[sep+1:]), 10, 64)
+	if err != nil || max < min {
+		return 0, 0, invalid
+	}
+
+	if min < -maxRandomBound || max > maxRandomBound {
+		return 0, 0, fmt.Errorf("invalid range \"%s\" for ${random}, bounds must be between %d and %d", arg, -maxRandomBound, maxRandomBound)
+	}
+
+	return min, max, nil
+}
+
+// Parses the time zone argument of ${time}, defaults to UTC.
+func parseLocation(arg string) (*time.Location, error) {
+	if arg == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(arg)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone \"%s\" for ${time}, expected e.g. Europe/Berlin", arg)
+	}
+
+	return location, nil
+}
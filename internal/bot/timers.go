@@ -0,0 +1,401 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/helix"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+const (
+	// How often the scheduler checks for due timers.
+	timerTick = 30 * time.Second
+
+	defaultTimerInterval = 15
+	defaultTimerMinLines = 5
+)
+
+// State of a timer since its last post.
+type timerState struct {
+	lastPost time.Time
+	// Value of the channel's line counter when the timer was posted.
+	lineMark int
+}
+
+// Posts the timers of every channel while the channel is live.
+//
+// At most one timer per channel is posted per tick, due timers take turns.
+type timerScheduler struct {
+	bot *TwitchBot
+
+	mu sync.Mutex
+	// Chat lines per channel since the scheduler started.
+	lines map[string]int
+	// Keyed by timer ID.
+	states map[int]timerState
+	// ID of the timer posted last per channel, the next due timer after it is posted next.
+	lastPosted map[string]int
+	started    time.Time
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// Inits and starts a new timer scheduler.
+func newTimerScheduler(bot *TwitchBot) *timerScheduler {
+	s := timerScheduler{}
+
+	s.bot = bot
+	s.lines = make(map[string]int)
+	s.states = make(map[int]timerState)
+	s.lastPosted = make(map[string]int)
+	s.started = time.Now()
+	s.done = make(chan struct{})
+	s.stopped = make(chan struct{})
+
+	go s.run()
+
+	return &s
+}
+
+// Counts a chat line of the channel.
+func (s *timerScheduler) CountLine(channel string) {
+	s.mu.Lock()
+	s.lines[channel]++
+	s.mu.Unlock()
+}
+
+// Stops the scheduler and waits for it to finish.
+func (s *timerScheduler) Stop() {
+	close(s.done)
+	<-s.stopped
+}
+
+func (s *timerScheduler) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(timerTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, channel := range s.bot.Channels {
+				if err := s.tick(channel); err != nil {
+					logging.WriteError(err)
+				}
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Posts the next due timer of the channel, if the channel is live.
+func (s *timerScheduler) tick(channel *Channel) error {
+	timers, err := s.bot.Service.GetAllTimers(channel.Name)
+	if err != nil {
+		return err
+	}
+
+	timer, ok := s.nextDue(channel.Name, timers, time.Now())
+	if !ok {
+		return nil
+	}
+
+	// Only ask Twitch if there is something to post.
+	live, err := s.bot.isLive(channel)
+	if err != nil || !live {
+		return err
+	}
+
+	s.mu.Lock()
+	s.states[timer.ID] = timerState{lastPost: time.Now(), lineMark: s.lines[channel.Name]}
+	s.lastPosted[channel.Name] = timer.ID
+	s.mu.Unlock()
+
+	s.bot.SendMessage(channel.Name, timer.Message)
+
+	return nil
+}
+
+// Returns the first due timer after the one posted last, timers are ordered by ID.
+func (s *timerScheduler) nextDue(channel string, timers []database.Timer, now time.Time) (database.Timer, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(timers) == 0 {
+		return database.Timer{}, false
+	}
+
+	// Start after the timer posted last.
+	start := 0
+	for i, timer := range timers {
+		if timer.ID == s.lastPosted[channel] {
+			start = i + 1
+			break
+		}
+	}
+
+	for i := range timers {
+		timer := timers[(start+i)%len(timers)]
+
+		if !timer.Enabled {
+			continue
+		}
+
+		state, ok := s.states[timer.ID]
+		if !ok {
+			// Timers wait a full interval after startup or after they were added.
+			state = timerState{lastPost: s.started}
+			if timer.Added.After(s.started) {
+				state.lastPost = timer.Added
+			}
+			state.lineMark = s.lines[channel]
+			s.states[timer.ID] = state
+		}
+
+		if now.Sub(state.lastPost) < time.Duration(timer.Interval)*time.Minute {
+			continue
+		}
+
+		if s.lines[channel]-state.lineMark < timer.MinLines {
+			continue
+		}
+
+		return timer, true
+	}
+
+	return database.Timer{}, false
+}
+
+// Returns true if the channel is live right now.
+func (b *TwitchBot) isLive(channel *Channel) (bool, error) {
+	if channel.ID() == "" {
+		return false, nil
+	}
+
+	_, err := b.Helix.GetStream(channel.ID())
+	if errors.Is(err, helix.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not check if %s is live: %w", channel.Name, err)
+	}
+
+	return true, nil
+}
+
+// Options of !timers add / edit, nil if not specified in the message.
+type timerFlags struct {
+	interval *int
+	minLines *int
+	enabled  *bool
+}
+
+// Splits the words of a !timers add / edit message into flags and the timer message.
+//
+// Supported flags: -i=<minutes>, -l=<chat lines>, -on=<true|false>
+func parseTimerFlags(words []string) (timerFlags, []string, error) {
+	flags := timerFlags{}
+	output := []string{}
+
+	for _, word := range words {
+		switch {
+		case strings.HasPrefix(word, "-i="):
+			value := strings.TrimPrefix(word, "-i=")
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return flags, nil, fmt.Errorf("Invalid interval specified: %s", value)
+			}
+			flags.interval = &interval
+
+		case strings.HasPrefix(word, "-l="):
+			value := strings.TrimPrefix(word, "-l=")
+			lines, err := strconv.Atoi(value)
+			if err != nil || lines < 0 {
+				return flags, nil, fmt.Errorf("Invalid chat lines specified: %s", value)
+			}
+			flags.minLines = &lines
+
+		case strings.HasPrefix(word, "-on="):
+			value := strings.TrimPrefix(word, "-on=")
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return flags, nil, fmt.Errorf("Invalid on specified: %s", value)
+			}
+			flags.enabled = &enabled
+
+		default:
+			output = append(output, word)
+		}
+	}
+
+	return flags, output, nil
+}
+
+// Handles the built-in !timers command, args are the words after "!timers".
+//
+// expected format:
+//
+//	!timers add <name> <message> (-i=<minutes>) (-l=<chat lines>)
+//	!timers edit <name> (<message>) (-i=<minutes>) (-l=<chat lines>) (-on=<true|false>)
+//	!timers delete <name>
+//	!timers (list)
+func (b *TwitchBot) timersCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	if !channel.HasUserLevel(message, types.Moderator) {
+		return "You are not allowed to use that command."
+	}
+
+	subCommand := "list"
+	if len(args) > 0 {
+		subCommand = args[0]
+	}
+
+	if subCommand != "list" && len(args) < 2 {
+		return "Missing timer name."
+	}
+
+	switch subCommand {
+	case "add":
+		flags, output, err := parseTimerFlags(args[2:])
+		if err != nil {
+			return err.Error()
+		}
+
+		timer := database.Timer{}
+		timer.Channel = channel.Name
+		timer.Name = strings.ToLower(args[1])
+		timer.Message = strings.Join(output, " ")
+		timer.Interval = defaultTimerInterval
+		timer.MinLines = defaultTimerMinLines
+		timer.Enabled = true
+		timer.Added = time.Now()
+
+		if flags.interval != nil {
+			timer.Interval = *flags.interval
+		}
+
+		if flags.minLines != nil {
+			timer.MinLines = *flags.minLines
+		}
+
+		if flags.enabled != nil {
+			timer.Enabled = *flags.enabled
+		}
+
+		if timer.Message == "" {
+			return "Missing timer message."
+		}
+
+		if err := b.Service.AddTimer(timer); err != nil {
+			if err == sql.ErrTxDone {
+				return fmt.Sprintf("Timer %s already exists.", timer.Name)
+			}
+			return err.Error()
+		}
+
+		b.recordEvent(channel.Name, types.TimerAdded, types.TimerEvent{
+			Issuer:    message.User.Name,
+			TimerName: timer.Name,
+		})
+
+		return fmt.Sprintf("Timer %s has successfully been added (every %d minutes, at least %d chat lines).",
+			timer.Name, timer.Interval, timer.MinLines)
+
+	case "edit":
+		flags, output, err := parseTimerFlags(args[2:])
+		if err != nil {
+			return err.Error()
+		}
+
+		// Keep old values for everything which was not specified.
+		timer, err := b.Service.GetOneTimer(channel.Name, strings.ToLower(args[1]))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Sprintf("Timer %s does not exist.", strings.ToLower(args[1]))
+			}
+			return err.Error()
+		}
+
+		if len(output) > 0 {
+			timer.Message = strings.Join(output, " ")
+		}
+
+		if flags.interval != nil {
+			timer.Interval = *flags.interval
+		}
+
+		if flags.minLines != nil {
+			timer.MinLines = *flags.minLines
+		}
+
+		if flags.enabled != nil {
+			timer.Enabled = *flags.enabled
+		}
+
+		timer.Edited.Time = time.Now()
+
+		if _, err := b.Service.UpdateTimer(timer); err != nil {
+			return err.Error()
+		}
+
+		b.recordEvent(channel.Name, types.TimerEdited, types.TimerEvent{
+			Issuer:    message.User.Name,
+			TimerName: timer.Name,
+		})
+
+		return fmt.Sprintf("Timer %s has successfully been updated.", timer.Name)
+
+	case "delete":
+		name := strings.ToLower(args[1])
+
+		if err := b.Service.DeleteTimer(channel.Name, name); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Sprintf("Timer %s does not exist.", name)
+			}
+			return err.Error()
+		}
+
+		b.recordEvent(channel.Name, types.TimerDeleted, types.TimerEvent{
+			Issuer:    message.User.Name,
+			TimerName: name,
+		})
+
+		return fmt.Sprintf("Timer %s has successfully been deleted.", name)
+
+	case "list":
+		timers, err := b.Service.GetAllTimers(channel.Name)
+		if err != nil {
+			return err.Error()
+		}
+
+		if len(timers) == 0 {
+			return "No timers on database yet."
+		}
+
+		names := []string{}
+
+		for _, timer := range timers {
+			state := fmt.Sprintf("%dm/%d lines", timer.Interval, timer.MinLines)
+			if !timer.Enabled {
+				state = "off"
+			}
+			names = append(names, fmt.Sprintf("%s (%s)", timer.Name, state))
+		}
+
+		return strings.Join(names, ", ")
+
+	default:
+		return fmt.Sprintf("Invalid subcommand: %s", subCommand)
+	}
+}
@@ -10,12 +10,15 @@ func _() {
 	var x [1]struct{}
 	_ = x[Anyone-0]
 	_ = x[Moderator-1]
-	_ = x[Owner-2]
+	_ = x[Broadcaster-2]
+	_ = x[Subscriber-3]
+	_ = x[VIP-4]
+	_ = x[Editor-5]
 }
 
-const _UserLevel_name = "AnyoneModeratorOwner"
+const _UserLevel_name = "AnyoneModeratorBroadcasterSubscriberVIPEditor"
 
-var _UserLevel_index = [...]uint8{0, 6, 15, 20}
+var _UserLevel_index = [...]uint8{0, 6, 15, 26, 36, 39, 45}
 
 func (i UserLevel) String() string {
 	if i < 0 || i >= UserLevel(len(_UserLevel_index)-1) {
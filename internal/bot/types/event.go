@@ -8,8 +8,43 @@ const (
 	CommandDeleted EventType = "comm_deleted"
 	CommandCalled  EventType = "comm_called"
 
+	CommandAliasAdded   EventType = "comm_alias_added"
+	CommandAliasRemoved EventType = "comm_alias_removed"
+
 	UserTimeout EventType = "user_timeout"
 	UserBan     EventType = "user_ban"
+
+	MessageDeleted EventType = "message_deleted"
+
+	UserSub          EventType = "user_sub"
+	UserResub        EventType = "user_resub"
+	UserSubGift      EventType = "user_subgift"
+	UserMysteryGift  EventType = "user_mysterygift"
+	UserGiftUpgrade  EventType = "user_giftupgrade"
+	ChannelRaid      EventType = "channel_raid"
+	ChannelAnnounced EventType = "channel_announcement"
+
+	CounterChanged EventType = "counter_changed"
+
+	TimerAdded   EventType = "timer_added"
+	TimerEdited  EventType = "timer_edited"
+	TimerDeleted EventType = "timer_deleted"
+
+	QuoteAdded   EventType = "quote_added"
+	QuoteDeleted EventType = "quote_deleted"
+
+	GiveawayStarted EventType = "giveaway_started"
+	GiveawayClosed  EventType = "giveaway_closed"
+	GiveawayDrawn   EventType = "giveaway_drawn"
+
+	PointsAdded EventType = "points_added"
+	PointsGiven EventType = "points_given"
+
+	SongSkipped         EventType = "song_skipped"
+	SongRequestsCleared EventType = "song_requests_cleared"
+
+	ChannelTitleChanged EventType = "channel_title_changed"
+	ChannelGameChanged  EventType = "channel_game_changed"
 )
 
 type CommandEvent struct {
@@ -17,7 +52,116 @@ type CommandEvent struct {
 	CommandName string `json:"command_name"`
 }
 
+type CommandAliasEvent struct {
+	Issuer      string `json:"issuer"`
+	Alias       string `json:"alias"`
+	CommandName string `json:"command_name"`
+}
+
+type CounterEvent struct {
+	Issuer  string `json:"issuer"`
+	Counter string `json:"counter"`
+	Action  string `json:"action"`
+	Value   int    `json:"value"`
+}
+
+type TimerEvent struct {
+	Issuer    string `json:"issuer"`
+	TimerName string `json:"timer_name"`
+}
+
+type QuoteEvent struct {
+	Issuer     string `json:"issuer"`
+	Number     int    `json:"number"`
+	Text       string `json:"text"`
+	QuotedUser string `json:"quoted_user"`
+}
+
+type GiveawayEvent struct {
+	Issuer     string `json:"issuer"`
+	GiveawayID int    `json:"giveaway_id"`
+	Keyword    string `json:"keyword"`
+	Winner     string `json:"winner,omitempty"`
+	Seed       int64  `json:"seed,omitempty"`
+}
+
+type PointsEvent struct {
+	Issuer string `json:"issuer"`
+	Target string `json:"target"`
+	Amount int    `json:"amount"`
+}
+
+type SongEvent struct {
+	Issuer  string `json:"issuer"`
+	Track   string `json:"track,omitempty"`
+	Cleared int    `json:"cleared,omitempty"`
+}
+
+type ChannelInfoEvent struct {
+	Issuer string `json:"issuer"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+	GameID string `json:"game_id,omitempty"`
+}
+
 type UserEvent struct {
 	Target   string `json:"target"`
 	Duration int    `json:"duration"`
 }
+
+type MessageDeleteEvent struct {
+	Channel   string `json:"channel"`
+	Target    string `json:"target"`
+	MessageID string `json:"message_id"`
+	Content   string `json:"content"`
+	ByBot     bool   `json:"by_bot"`
+}
+
+type SubEvent struct {
+	Channel          string `json:"channel"`
+	User             string `json:"user"`
+	Tier             string `json:"tier"`
+	CumulativeMonths int    `json:"cumulative_months"`
+	StreakMonths     int    `json:"streak_months"`
+	Message          string `json:"message"`
+}
+
+type GiftSubEvent struct {
+	Channel   string `json:"channel"`
+	Gifter    string `json:"gifter"`
+	Recipient string `json:"recipient"`
+	Tier      string `json:"tier"`
+	Months    int    `json:"months"`
+	Anonymous bool   `json:"anonymous"`
+	// Set if the sub is part of a mystery (community) gift, which is thanked as a whole.
+	CommunityGift bool `json:"community_gift,omitempty"`
+}
+
+type MysteryGiftEvent struct {
+	Channel     string `json:"channel"`
+	Gifter      string `json:"gifter"`
+	Tier        string `json:"tier"`
+	Count       int    `json:"count"`
+	SenderTotal int    `json:"sender_total"`
+	Anonymous   bool   `json:"anonymous"`
+}
+
+type GiftUpgradeEvent struct {
+	Channel string `json:"channel"`
+	User    string `json:"user"`
+	Gifter  string `json:"gifter"`
+	Tier    string `json:"tier"`
+}
+
+type RaidEvent struct {
+	Channel string `json:"channel"`
+	Raider  string `json:"raider"`
+	Viewers int    `json:"viewers"`
+}
+
+type AnnouncementEvent struct {
+	Channel string `json:"channel"`
+	User    string `json:"user"`
+	Color   string `json:"color"`
+	Message string `json:"message"`
+}
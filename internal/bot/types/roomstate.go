@@ -0,0 +1,38 @@
+package types
+
+// Current chat modes of a Twitch channel.
+type RoomState struct {
+	EmoteOnly bool
+	// -1 if disabled, else minimum follow duration in minutes.
+	FollowersOnly int
+	R9K           bool
+	// Seconds a user has to wait between messages, 0 if disabled.
+	Slow     int
+	SubsOnly bool
+}
+
+// Returns the default room state (every mode disabled).
+func DefaultRoomState() RoomState {
+	return RoomState{FollowersOnly: -1}
+}
+
+// Applies the (partial) state of a ROOMSTATE message and returns the new room state.
+//
+// Twitch sends every mode on join, but only the changed mode afterwards.
+func (r RoomState) Apply(state map[string]int) RoomState {
+	for mode, value := range state {
+		switch mode {
+		case "emote-only":
+			r.EmoteOnly = value == 1
+		case "followers-only":
+			r.FollowersOnly = value
+		case "r9k":
+			r.R9K = value == 1
+		case "slow":
+			r.Slow = value
+		case "subs-only":
+			r.SubsOnly = value == 1
+		}
+	}
+	return r
+}
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level a user needs to use a command. The values are stored on the database, only append new levels.
+//
+// Use Rank() or Satisfies() to compare levels, the values are not ordered.
+type UserLevel int
+
+const (
+	Anyone UserLevel = iota
+	Moderator
+	Broadcaster
+	Subscriber
+	VIP
+	Editor
+)
+
+// Returns the rank of the user level, higher ranks include every lower rank.
+func (l UserLevel) Rank() int {
+	switch l {
+	case Subscriber:
+		return 1
+	case VIP:
+		return 2
+	case Moderator:
+		return 3
+	case Editor:
+		return 4
+	case Broadcaster:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// Returns true if a user with this level may use something which requires the required level.
+func (l UserLevel) Satisfies(required UserLevel) bool {
+	return l.Rank() >= required.Rank()
+}
+
+// Converts a user level from chat (e.g. "-ul=mod") to the corresponding UserLevel.
+func ParseUserLevel(level string) (UserLevel, error) {
+	switch strings.ToLower(level) {
+	case "anyone", "everyone":
+		return Anyone, nil
+	case "subscriber", "sub":
+		return Subscriber, nil
+	case "vip":
+		return VIP, nil
+	case "moderator", "mod":
+		return Moderator, nil
+	case "editor":
+		return Editor, nil
+	// "owner" was the name of the broadcaster level before.
+	case "broadcaster", "owner":
+		return Broadcaster, nil
+	default:
+		return Anyone, fmt.Errorf("invalid userlevel specified: %s", level)
+	}
+}
+
+// Returns true if the user has the badge in any version.
+//
+// Badge versions are not flags, e.g. first-month subscribers have the badge subscriber/0.
+func HasBadge(badges map[string]int, badge string) bool {
+	_, ok := badges[badge]
+	return ok
+}
+
+// Returns true if the badges mark a subscriber.
+//
+// Founders are the first subscribers of a channel, Twitch shows their founder badge instead of the subscriber badge.
+func IsSubscriber(badges map[string]int) bool {
+	return HasBadge(badges, "subscriber") || HasBadge(badges, "founder")
+}
+
+// Resolves the highest user level of a chat user from their badges, the channel owner and the channel's editors.
+func ResolveUserLevel(badges map[string]int, username, owner string, editors []string) UserLevel {
+	username = strings.ToLower(username)
+
+	if HasBadge(badges, "broadcaster") || username == strings.ToLower(owner) {
+		return Broadcaster
+	}
+
+	for _, editor := range editors {
+		if username == strings.ToLower(editor) {
+			return Editor
+		}
+	}
+
+	switch {
+	case HasBadge(badges, "moderator"):
+		return Moderator
+	case HasBadge(badges, "vip"):
+		return VIP
+	case IsSubscriber(badges):
+		return Subscriber
+	default:
+		return Anyone
+	}
+}
@@ -0,0 +1,34 @@
+package types
+
+import "testing"
+
+func TestResolveUserLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		badges   map[string]int
+		username string
+		editors  []string
+		want     UserLevel
+	}{
+		{"no badges", nil, "viewer", nil, Anyone},
+		{"unrelated badge", map[string]int{"premium": 1}, "viewer", nil, Anyone},
+		{"first month subscriber", map[string]int{"subscriber": 0}, "viewer", nil, Subscriber},
+		{"subscriber", map[string]int{"subscriber": 1}, "viewer", nil, Subscriber},
+		{"long time subscriber", map[string]int{"subscriber": 3012}, "viewer", nil, Subscriber},
+		{"founder", map[string]int{"founder": 0}, "viewer", nil, Subscriber},
+		{"vip", map[string]int{"vip": 1}, "viewer", nil, VIP},
+		{"subscribed vip", map[string]int{"vip": 1, "subscriber": 0}, "viewer", nil, VIP},
+		{"moderator", map[string]int{"moderator": 1, "subscriber": 6}, "viewer", nil, Moderator},
+		{"editor", map[string]int{"moderator": 1}, "Editor", []string{"editor"}, Editor},
+		{"broadcaster badge", map[string]int{"broadcaster": 1}, "streamer", nil, Broadcaster},
+		{"owner without badge", nil, "Owner", nil, Broadcaster},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveUserLevel(tt.badges, tt.username, "owner", tt.editors); got != tt.want {
+				t.Errorf("ResolveUserLevel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,28 +3,42 @@ package bot
 import (
 	"time"
 
+	"github.com/devusSs/twitch-kraken/internal/bot/types"
 	"github.com/devusSs/twitch-kraken/internal/database"
 	"github.com/gempir/go-twitch-irc/v4"
 )
 
-// Function registers username on database. Cannot add any details like twitchid etc.
-func (b *TwitchBot) AddUserOnConnect(message twitch.UserJoinMessage) error {
-	return b.Service.RegisterTwitchUser(message.User, time.Now())
+// Function registers username on database and starts a viewer session. Cannot add any details like twitchid etc.
+func (b *TwitchBot) AddUserOnConnect(channel *Channel, message twitch.UserJoinMessage) error {
+	now := time.Now()
+
+	if err := b.Service.RegisterTwitchUser(channel.Name, message.User, now); err != nil {
+		return err
+	}
+
+	return b.Service.OpenViewerSession(channel.Name, message.User, now)
 }
 
-// Function updates user's last seen on database. Cannot add any details like twitchid etc.
-func (b *TwitchBot) EditUserOnDisconnect(message twitch.UserPartMessage) error {
-	return b.Service.UpdateTwitchUserDC(message.User, time.Now())
+// Function updates user's last seen on database and ends their viewer session. Cannot add any details like twitchid etc.
+func (b *TwitchBot) EditUserOnDisconnect(channel *Channel, message twitch.UserPartMessage) error {
+	now := time.Now()
+
+	if err := b.Service.UpdateTwitchUserDC(channel.Name, message.User, now); err != nil {
+		return err
+	}
+
+	return b.Service.CloseViewerSession(channel.Name, message.User, now)
 }
 
 // Function updates user's base details. This will add details like twitchid etc.
-func (b *TwitchBot) AddUserDetailsOnMessage(message twitch.PrivateMessage) error {
+func (b *TwitchBot) AddUserDetailsOnMessage(channel *Channel, message twitch.PrivateMessage) error {
 	user := database.TwitchUser{}
 
+	user.Channel = channel.Name
 	user.TwitchID = message.User.ID
 	user.Username = message.User.Name
 	user.DisplayName = message.User.DisplayName
-	if message.Tags["mod"] == "1" || user.Username == b.Owner {
+	if message.Tags["mod"] == "1" || user.Username == channel.Owner {
 		user.IsMod.Bool = true
 	} else {
 		user.IsMod.Bool = false
@@ -35,9 +49,10 @@ func (b *TwitchBot) AddUserDetailsOnMessage(message twitch.PrivateMessage) error
 }
 
 // Function updates user's details on ban events (no timeouts yet). Some details like ismod may be missing.
-func (b *TwitchBot) AddUserDetailsOnBan(message twitch.ClearChatMessage) error {
+func (b *TwitchBot) AddUserDetailsOnBan(channel *Channel, message twitch.ClearChatMessage) error {
 	user := database.TwitchUser{}
 
+	user.Channel = channel.Name
 	user.TwitchID = message.TargetUserID
 	user.Username = message.TargetUsername
 	user.LastSeen.Time = time.Now()
@@ -47,11 +62,14 @@ func (b *TwitchBot) AddUserDetailsOnBan(message twitch.ClearChatMessage) error {
 	return b.Service.UpdateTwitchUserOnBan(user)
 }
 
-// Function to check if a user is mod or owner of the bot.
-func (b *TwitchBot) IsUserModOrOwner(message twitch.PrivateMessage) bool {
-	user := message.User
-	if user.Badges["moderator"] == 1 || user.Name == b.Owner {
-		return true
-	}
-	return false
+// Function to resolve the user level of a chat user in this channel.
+//
+// Every permission check should use this, so badges and editors are treated the same everywhere.
+func (c *Channel) UserLevel(message twitch.PrivateMessage) types.UserLevel {
+	return types.ResolveUserLevel(message.User.Badges, message.User.Name, c.Owner, c.Editors)
+}
+
+// Function to check if a user has at least the required user level in this channel.
+func (c *Channel) HasUserLevel(message twitch.PrivateMessage, required types.UserLevel) bool {
+	return c.UserLevel(message).Satisfies(required)
 }
@@ -0,0 +1,34 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/utils"
+	"github.com/gempir/go-twitch-irc/v4"
+)
+
+// Handles the built-in !watchtime command, shows the watch time of the user or the specified user.
+//
+// expected format: !watchtime (<user>)
+func (b *TwitchBot) watchTimeCommand(channel *Channel, message twitch.PrivateMessage, args []string) string {
+	username := message.User.Name
+	displayName := message.User.DisplayName
+
+	if len(args) > 0 && args[0] != "" {
+		displayName = strings.TrimPrefix(args[0], "@")
+		username = strings.ToLower(displayName)
+	}
+
+	watchTime, err := b.Service.GetWatchTime(channel.Name, username, time.Now())
+	if err != nil {
+		return err.Error()
+	}
+
+	if watchTime < time.Minute {
+		return fmt.Sprintf("%s has not been watching yet.", displayName)
+	}
+
+	return fmt.Sprintf("%s has been watching for %s.", displayName, utils.FormatDuration(watchTime))
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 )
 
 type Config struct {
@@ -15,11 +16,13 @@ type Config struct {
 		Database string `json:"database"`
 	} `json:"postgres"`
 	Twitch struct {
-		BotLogin    string   `json:"bot_login"`    // bot login name / username
-		BotPassword string   `json:"bot_password"` // oauth token: https://twitchapps.com/tmi/
-		JoinChannel string   `json:"join_channel"` // which IRC / Twitch channel the bot should join on start
-		BotOwner    string   `json:"bot_owner"`    // actual bot owner / channel owner, usually matches JoinChannel
-		Editors     []string `json:"editors"`      // not mods, users with access to internal bot commands
+		BotLogin    string    `json:"bot_login"`    // bot login name / username
+		BotPassword string    `json:"bot_password"` // oauth token: https://twitchapps.com/tmi/
+		Channels    []Channel `json:"channels"`     // which IRC / Twitch channels the bot should join on start
+		// Deprecated: single channel setup, use Channels instead. Will be converted on CheckConfig().
+		JoinChannel string   `json:"join_channel,omitempty"`
+		BotOwner    string   `json:"bot_owner,omitempty"`
+		Editors     []string `json:"editors,omitempty"`
 	} `json:"twitch"`
 	// Since the bot needs access to user resources as well as api resources
 	// we will need developer specific vars.
@@ -29,6 +32,7 @@ type Config struct {
 		ClientSecret string `json:"client_secret"`
 		RedirectURL  string `json:"redirect_url"`
 		SecureCookie string `json:"secure_cookie"`
+		HelixURL     string `json:"helix_url"` // optional, defaults to https://api.twitch.tv/helix
 	} `json:"twitch_auth"`
 	// Since the bot needs access to user resources as well as api resources
 	// we will need developer specific vars.
@@ -38,11 +42,73 @@ type Config struct {
 		ClientSecret string `json:"client_secret"`
 		RedirectURL  string `json:"redirect_url"`
 		SecureCookie string `json:"secure_cookie"`
+		APIURL       string `json:"api_url"` // optional, defaults to https://api.spotify.com/v1
+		Channel      string `json:"channel"` // Twitch channel of the Spotify account's owner, defaults to the first channel
 	} `json:"spotify_auth"`
+	// Background poller which logs every Spotify track to the song history and announces track changes.
+	NowPlaying struct {
+		Enabled     bool `json:"enabled"`
+		Interval    int  `json:"interval"`     // seconds between two polls, defaults to 15
+		AnnounceGap int  `json:"announce_gap"` // seconds between two announcements in a channel, defaults to 60
+	} `json:"now_playing"`
 	Command struct {
-		Prefix          string `json:"prefix"`           // prefix to call commands from chat, like "!" or "."
-		DefaultCooldown int    `json:"default_cooldown"` // usually 0 or < 5 seconds
+		Prefixes         []string `json:"prefixes"`          // prefixes to call commands from chat, like "!" or "?"
+		DefaultCooldown  int      `json:"default_cooldown"`  // used if a command is added without -cd=, usually 0 or < 5 seconds
+		PersistCooldowns bool     `json:"persist_cooldowns"` // store cooldowns on the database so they survive a restart
+		// Deprecated: single prefix setup, use Prefixes instead. Will be converted on CheckConfig().
+		Prefix string `json:"prefix,omitempty"`
 	} `json:"command"`
+	// How the bot answers chat messages: "reply" threads the answer to the message,
+	// "mention" sends a normal message starting with "@user".
+	Responses struct {
+		Commands   string `json:"commands"`   // answers to commands, defaults to "reply"
+		GateKeeper string `json:"gatekeeper"` // reasons for Gatekeeper actions, defaults to "mention" since the message gets deleted
+	} `json:"responses"`
+	// Loyalty points, the channel's own currency. Points are only earned while the stream is live.
+	Points struct {
+		WatchPoints   int     `json:"watch_points"`   // points per watch interval for every viewer
+		WatchInterval int     `json:"watch_interval"` // minutes, defaults to 10
+		ChatPoints    int     `json:"chat_points"`    // points per chat message
+		ChatCooldown  int     `json:"chat_cooldown"`  // seconds until a user's next message earns points again, defaults to 60
+		SubMultiplier float64 `json:"sub_multiplier"` // defaults to 1
+		VIPMultiplier float64 `json:"vip_multiplier"` // defaults to 1
+	} `json:"points"`
+	// Song requests via !sr, added to the Spotify queue of the spotify_auth account.
+	SongRequests struct {
+		Enabled        bool     `json:"enabled"`
+		Channel        string   `json:"channel"`         // channel which may request songs, defaults to the Spotify channel
+		MaxPerUser     int      `json:"max_per_user"`    // waiting requests per user, mods are not limited, defaults to 2
+		MaxLength      int      `json:"max_length"`      // seconds, 0 for no limit
+		SkipVotes      int      `json:"skip_votes"`      // !skip votes needed to skip a track, mods skip right away, defaults to 3
+		BlockedTracks  []string `json:"blocked_tracks"`  // track links, URIs or IDs
+		BlockedArtists []string `json:"blocked_artists"` // artist IDs or names
+	} `json:"song_requests"`
+	Quotes struct {
+		SubsCanAdd bool `json:"subs_can_add"` // subscribers may use !quote add, otherwise only mods
+	} `json:"quotes"`
+	// Thank-you templates for USERNOTICE events like subs or raids.
+	// Leave a template empty to not respond to that event at all.
+	//
+	// Available variables: {user}, {recipient}, {tier}, {months}, {streak}, {count}, {viewers}
+	Notices struct {
+		Sub         string `json:"sub"`
+		Resub       string `json:"resub"`
+		SubGift     string `json:"sub_gift"`
+		MysteryGift string `json:"mystery_gift"`
+		GiftUpgrade string `json:"gift_upgrade"`
+		Raid        string `json:"raid"`
+	} `json:"notices"`
+}
+
+// Twitch channel the bot should join. Commands, Gatekeeper settings and users are stored per channel.
+type Channel struct {
+	Name    string   `json:"name"`    // Twitch channel name without leading "#"
+	Owner   string   `json:"owner"`   // actual channel owner, usually matches Name
+	Editors []string `json:"editors"` // not mods, users with access to internal bot commands
+	// Name of the channel's loyalty points, defaults to "points".
+	Currency string `json:"currency"`
+	// Post a "Now playing" message on track changes, needs now_playing to be enabled.
+	AnnounceSongs bool `json:"announce_songs"`
 }
 
 // Instances new config from json file, but does not check for any missing keys or errors.
@@ -94,12 +160,42 @@ func (c *Config) CheckConfig() error {
 		return fmt.Errorf("missing key: twitch bot password")
 	}
 
-	if c.Twitch.JoinChannel == "" {
-		return fmt.Errorf("missing key: twitch join channel")
+	// Convert the old single channel setup.
+	if len(c.Twitch.Channels) == 0 && c.Twitch.JoinChannel != "" {
+		c.Twitch.Channels = append(c.Twitch.Channels, Channel{
+			Name:    c.Twitch.JoinChannel,
+			Owner:   c.Twitch.BotOwner,
+			Editors: c.Twitch.Editors,
+		})
+	}
+
+	if len(c.Twitch.Channels) == 0 {
+		return fmt.Errorf("missing key: twitch channels")
 	}
 
-	if c.Twitch.BotOwner == "" {
-		return fmt.Errorf("missing key: twitch bot owner")
+	for i, channel := range c.Twitch.Channels {
+		if channel.Name == "" {
+			return fmt.Errorf("missing key: twitch channel name (channel %d)", i)
+		}
+
+		if channel.Owner == "" {
+			return fmt.Errorf("missing key: twitch channel owner (channel %s)", channel.Name)
+		}
+
+		c.Twitch.Channels[i].Name = strings.ToLower(strings.TrimPrefix(channel.Name, "#"))
+		c.Twitch.Channels[i].Owner = strings.ToLower(channel.Owner)
+
+		editors := []string{}
+		for _, editor := range channel.Editors {
+			if editor != "" {
+				editors = append(editors, strings.ToLower(editor))
+			}
+		}
+		c.Twitch.Channels[i].Editors = editors
+
+		if channel.Currency == "" {
+			c.Twitch.Channels[i].Currency = "points"
+		}
 	}
 
 	if c.TwitchAuth.ClientID == "" {
@@ -134,8 +230,93 @@ func (c *Config) CheckConfig() error {
 		return fmt.Errorf("missing key: spotify auth secure cookie")
 	}
 
-	if c.Command.Prefix == "" {
-		return fmt.Errorf("missing key: command prefix")
+	// Convert the old single prefix setup.
+	if len(c.Command.Prefixes) == 0 && c.Command.Prefix != "" {
+		c.Command.Prefixes = append(c.Command.Prefixes, c.Command.Prefix)
+	}
+
+	if len(c.Command.Prefixes) == 0 {
+		return fmt.Errorf("missing key: command prefixes")
+	}
+
+	for _, prefix := range c.Command.Prefixes {
+		if prefix == "" || strings.ContainsAny(prefix, " \t") {
+			return fmt.Errorf("invalid value: command prefix \"%s\" must not be empty or contain spaces", prefix)
+		}
+	}
+
+	if c.Command.DefaultCooldown < 0 {
+		return fmt.Errorf("invalid value: command default cooldown must not be negative")
+	}
+
+	if c.Points.WatchPoints < 0 || c.Points.ChatPoints < 0 || c.Points.WatchInterval < 0 || c.Points.ChatCooldown < 0 {
+		return fmt.Errorf("invalid value: points settings must not be negative")
+	}
+
+	if c.Points.WatchInterval == 0 {
+		c.Points.WatchInterval = 10
+	}
+
+	if c.Points.ChatCooldown == 0 {
+		c.Points.ChatCooldown = 60
+	}
+
+	if c.Points.SubMultiplier <= 0 {
+		c.Points.SubMultiplier = 1
+	}
+
+	if c.Points.VIPMultiplier <= 0 {
+		c.Points.VIPMultiplier = 1
+	}
+
+	if c.SpotifyAuth.Channel == "" {
+		c.SpotifyAuth.Channel = c.Twitch.Channels[0].Name
+	}
+
+	c.SpotifyAuth.Channel = strings.ToLower(strings.TrimPrefix(c.SpotifyAuth.Channel, "#"))
+
+	if c.SongRequests.Channel == "" {
+		c.SongRequests.Channel = c.SpotifyAuth.Channel
+	}
+
+	c.SongRequests.Channel = strings.ToLower(strings.TrimPrefix(c.SongRequests.Channel, "#"))
+
+	if c.SongRequests.MaxPerUser < 0 || c.SongRequests.MaxLength < 0 || c.SongRequests.SkipVotes < 0 {
+		return fmt.Errorf("invalid value: song request settings must not be negative")
+	}
+
+	if c.SongRequests.MaxPerUser == 0 {
+		c.SongRequests.MaxPerUser = 2
+	}
+
+	if c.SongRequests.SkipVotes == 0 {
+		c.SongRequests.SkipVotes = 3
+	}
+
+	if c.NowPlaying.Interval < 0 || c.NowPlaying.AnnounceGap < 0 {
+		return fmt.Errorf("invalid value: now playing settings must not be negative")
+	}
+
+	if c.NowPlaying.Interval == 0 {
+		c.NowPlaying.Interval = 15
+	}
+
+	if c.NowPlaying.AnnounceGap == 0 {
+		c.NowPlaying.AnnounceGap = 60
+	}
+
+	if c.Responses.Commands == "" {
+		c.Responses.Commands = "reply"
+	}
+
+	if c.Responses.GateKeeper == "" {
+		c.Responses.GateKeeper = "mention"
+	}
+
+	for _, mode := range []string{c.Responses.Commands, c.Responses.GateKeeper} {
+		if mode != "reply" && mode != "mention" {
+			return fmt.Errorf("invalid value: responses must be \"reply\" or \"mention\", got \"%s\"", mode)
+		}
 	}
 
 	return nil
@@ -2,39 +2,106 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/devusSs/twitch-kraken/internal/bot/types"
 	"github.com/lib/pq"
 )
 
+// Returned if a user does not have enough loyalty points to spend or give.
+var ErrInsufficientPoints = errors.New("insufficient points")
+
 // Service layer for the Postgres connection.
 type Service interface {
 	Ping() error
 	Close() error
 	Migrate() error
 
-	LoadGateKeeperSettings() (GateKeeperSettings, error)
+	LoadGateKeeperSettings(string) (GateKeeperSettings, error)
 	UpdateGateKeeperSettings(GateKeeperSettings) error
 
-	RegisterTwitchUser(string, time.Time) error
-	UpdateTwitchUserDC(string, time.Time) error
+	RegisterTwitchUser(string, string, time.Time) error
+	UpdateTwitchUserDC(string, string, time.Time) error
 	UpdateTwitchUserBaseDetails(TwitchUser) error
 	UpdateTwitchUserOnBan(TwitchUser) error
+	GetTwitchUser(string, string) (TwitchUser, error)
+
+	OpenViewerSession(string, string, time.Time) error
+	CloseViewerSession(string, string, time.Time) error
+	CloseAllViewerSessions(string, time.Time) error
+	CloseStaleViewerSessions(string) error
+	GetWatchTime(string, string, time.Time) (time.Duration, error)
+	GetOpenViewerSessions(string) ([]string, error)
 
 	AddTwitchCommand(TwitchCommand) error
 	UpdateTwitchCommand(TwitchCommand) (TwitchCommand, error)
-	DeleteTwitchCommand(string) error
-	GetAllTwitchCommands() ([]TwitchCommand, error)
-	GetOneTwitchCommand(string) (TwitchCommand, error)
+	DeleteTwitchCommand(string, string) error
+	GetAllTwitchCommands(string) ([]TwitchCommand, error)
+	GetOneTwitchCommand(string, string) (TwitchCommand, error)
+	IncrementTwitchCommandUses(string, string) (int, error)
+
+	AddCommandAlias(CommandAlias) error
+	DeleteCommandAlias(string, string) error
+	GetAllCommandAliases(string) ([]CommandAlias, error)
+
+	AddTimer(Timer) error
+	UpdateTimer(Timer) (Timer, error)
+	DeleteTimer(string, string) error
+	GetAllTimers(string) ([]Timer, error)
+	GetOneTimer(string, string) (Timer, error)
+
+	AddQuote(Quote) (Quote, error)
+	GetQuote(string, int) (Quote, error)
+	GetRandomQuote(string) (Quote, error)
+	SearchQuotes(string, string) ([]Quote, error)
+	DeleteQuote(string, int) error
+
+	AddGiveaway(Giveaway) (Giveaway, error)
+	GetOpenGiveaway(string) (Giveaway, error)
+	GetLatestGiveaway(string) (Giveaway, error)
+	CloseGiveaway(int, time.Time) error
+	AddGiveawayEntry(GiveawayEntry) error
+	GetGiveawayEntries(int) ([]GiveawayEntry, error)
+	AddGiveawayDraw(GiveawayDraw) (GiveawayDraw, error)
+	GetGiveawayDraws(int) ([]GiveawayDraw, error)
+
+	GetPoints(string, string) (Points, error)
+	AddPoints(string, map[string]int) error
+	SpendPoints(string, string, int) (Points, error)
+	TransferPoints(string, string, string, int) error
+	GetTopPoints(string, int) ([]Points, error)
+
+	AddSongRequest(SongRequest) (SongRequest, error)
+	GetPendingSongRequests(string) ([]SongRequest, error)
+	MarkSongRequestQueued(int, time.Time) error
+	MarkSongRequestSkipped(string, string) error
+	ClearSongRequests(string) (int, error)
+
+	AddSongHistory(SongHistory) error
+	GetSongAt(string, time.Time, time.Time) (SongHistory, error)
+	GetStreamSongs(string, time.Time) ([]SongHistory, error)
+	GetLastStreamStart(string) (time.Time, error)
+
+	GetCounter(string, string) (Counter, error)
+	SetCounter(string, string, int) (Counter, error)
+	AdjustCounter(string, string, int) (Counter, error)
+
+	SetCommandCooldown(CommandCooldown) (CommandCooldown, error)
+	GetActiveCommandCooldowns(time.Time) ([]CommandCooldown, error)
+	DeleteExpiredCommandCooldowns(time.Time) error
 
 	AddAuthEvent(AuthEvent) (AuthEvent, error)
 	AddMessageEvent(MessageEvent) (MessageEvent, error)
+	CountMessageEvents(string, string) (int, error)
+	MarkMessageEventDeleted(string, bool, time.Time) error
+	AddRoomStateEvent(RoomStateEvent) (RoomStateEvent, error)
 }
 
 // Model for Gatekeeper settings.
 type GateKeeperSettings struct {
 	ID          int            `db:"id"`
+	Channel     string         `db:"channel"`
 	FilterChat  bool           `db:"filter_chat"`
 	FilterLinks bool           `db:"filter_links"`
 	IgnoreMods  bool           `db:"ignore_mods"`
@@ -48,6 +115,7 @@ type GateKeeperSettings struct {
 // Model for Twitch users on database.
 type TwitchUser struct {
 	ID            int          `db:"id"`
+	Channel       string       `db:"channel"`
 	TwitchID      string       `db:"twitchid"`
 	Username      string       `db:"username"`
 	DisplayName   string       `db:"displayname"`
@@ -60,13 +128,147 @@ type TwitchUser struct {
 
 // Model for Twitch commands which can be used by Twitch chat users.
 type TwitchCommand struct {
-	ID        int             `db:"id"`
-	Name      string          `db:"name"`
-	Output    string          `db:"output"`
-	Userlevel types.UserLevel `db:"userlevel"`
-	Cooldown  int             `db:"cooldown"`
-	Added     time.Time       `db:"added"`
-	Edited    sql.NullTime    `db:"edited"`
+	ID           int             `db:"id"`
+	Channel      string          `db:"channel"`
+	Name         string          `db:"name"`
+	Output       string          `db:"output"`
+	Userlevel    types.UserLevel `db:"userlevel"`
+	Cooldown     int             `db:"cooldown"`      // global cooldown in seconds
+	UserCooldown int             `db:"user_cooldown"` // cooldown in seconds per user
+	ModBypass    bool            `db:"mod_bypass"`    // moderators (and higher) may skip the cooldowns
+	Cost         int             `db:"cost"`          // loyalty points a call costs, 0 for free commands
+	Uses         int             `db:"uses"`          // how often the command has been called
+	Added        time.Time       `db:"added"`
+	Edited       sql.NullTime    `db:"edited"`
+}
+
+// Model for alternative names of a command, e.g. "dc" for "discord".
+type CommandAlias struct {
+	ID      int       `db:"id"`
+	Channel string    `db:"channel"`
+	Alias   string    `db:"alias"`
+	Command string    `db:"command"` // name of the actual command
+	Added   time.Time `db:"added"`
+}
+
+// Model for timers, messages the bot posts every Interval minutes while the stream is live.
+type Timer struct {
+	ID       int          `db:"id"`
+	Channel  string       `db:"channel"`
+	Name     string       `db:"name"`
+	Message  string       `db:"message"`
+	Interval int          `db:"interval_minutes"` // minutes between two posts of the timer
+	MinLines int          `db:"min_lines"`        // chat lines needed since the last post of the timer
+	Enabled  bool         `db:"enabled"`
+	Added    time.Time    `db:"added"`
+	Edited   sql.NullTime `db:"edited"`
+}
+
+// Model for quotes of the community, numbered per channel.
+type Quote struct {
+	ID         int          `db:"id"`
+	Channel    string       `db:"channel"`
+	Number     int          `db:"number"` // used for !quote <number>, unique per channel
+	Text       string       `db:"text"`
+	QuotedUser string       `db:"quoted_user"` // who said it
+	Author     string       `db:"author"`      // who added the quote
+	Game       string       `db:"game"`        // game / category of the channel when the quote was added
+	StreamDate sql.NullTime `db:"stream_date"` // start of the stream, null if the channel was offline
+	Added      time.Time    `db:"added"`
+}
+
+// Model for giveaways, users enter by sending the keyword in chat while the giveaway is open.
+type Giveaway struct {
+	ID            int          `db:"id"`
+	Channel       string       `db:"channel"`
+	Keyword       string       `db:"keyword"`
+	FollowersOnly bool         `db:"followers_only"`
+	SubsOnly      bool         `db:"subs_only"`
+	MinMessages   int          `db:"min_messages"` // chat messages a user needs to have sent in the channel
+	SeenBefore    sql.NullTime `db:"seen_before"`  // users must have been seen first before this date
+	SubLuck       int          `db:"sub_luck"`     // tickets of a subscriber, everyone else gets one
+	StartedBy     string       `db:"started_by"`
+	Started       time.Time    `db:"started"`
+	Closed        sql.NullTime `db:"closed"` // null while the giveaway accepts entries
+}
+
+// Model for entries of a giveaway, one per user.
+type GiveawayEntry struct {
+	ID          int       `db:"id"`
+	GiveawayID  int       `db:"giveaway_id"`
+	Username    string    `db:"username"`
+	DisplayName string    `db:"displayname"`
+	Tickets     int       `db:"tickets"`
+	Entered     time.Time `db:"entered"`
+}
+
+// Model for the audit record of a giveaway draw.
+//
+// The winner can be verified by drawing from Pool again with a math/rand source seeded with Seed.
+type GiveawayDraw struct {
+	ID         int       `db:"id"`
+	GiveawayID int       `db:"giveaway_id"`
+	Winner     string    `db:"winner"`
+	Seed       int64     `db:"seed"`
+	Pool       string    `db:"pool"`    // json list of the eligible entries in draw order
+	Tickets    int       `db:"tickets"` // sum of the tickets in Pool
+	Redraw     bool      `db:"redraw"`
+	DrawnBy    string    `db:"drawn_by"`
+	Drawn      time.Time `db:"drawn"`
+}
+
+// Model for loyalty point balances, the channel's own currency.
+type Points struct {
+	ID       int       `db:"id"`
+	Channel  string    `db:"channel"`
+	Username string    `db:"username"`
+	Balance  int       `db:"balance"`
+	Updated  time.Time `db:"updated"`
+}
+
+// Model for Spotify song requests via !sr.
+type SongRequest struct {
+	ID         int          `db:"id"`
+	Channel    string       `db:"channel"`
+	Username   string       `db:"username"`
+	TrackID    string       `db:"track_id"`
+	TrackURI   string       `db:"track_uri"`
+	Title      string       `db:"title"`
+	Artists    string       `db:"artists"`
+	DurationMS int          `db:"duration_ms"`
+	Status     string       `db:"status"` // pending, queued, skipped or cleared
+	Requested  time.Time    `db:"requested"`
+	Queued     sql.NullTime `db:"queued"` // when the request was added to the Spotify queue
+}
+
+// Model for the song history, every track played on the Spotify account.
+type SongHistory struct {
+	ID            int          `db:"id"`
+	Channel       string       `db:"channel"` // Twitch channel of the Spotify account's owner
+	TrackID       string       `db:"track_id"`
+	Title         string       `db:"title"`
+	Artists       string       `db:"artists"`
+	URL           string       `db:"url"`
+	Started       time.Time    `db:"started"`
+	StreamStarted sql.NullTime `db:"stream_started"` // start of the stream the track was played in, null if offline
+}
+
+// Model for named counters like a death counter, managed via !counter.
+type Counter struct {
+	ID      int       `db:"id"`
+	Channel string    `db:"channel"`
+	Name    string    `db:"name"`
+	Value   int       `db:"value"`
+	Updated time.Time `db:"updated"`
+}
+
+// Model for command cooldowns, only stored if cooldowns should survive a restart.
+type CommandCooldown struct {
+	ID        int       `db:"id"`
+	Channel   string    `db:"channel"`
+	Command   string    `db:"command"`
+	Username  string    `db:"username"` // empty for the global cooldown of the command
+	ExpiresAt time.Time `db:"expires_at"`
 }
 
 // Model for Auth Events like Command Added, Command Edited, Command Deleted, Timeouts, Bans, ...
@@ -74,6 +276,7 @@ type TwitchCommand struct {
 // Check the internal/bot/types/event.go file for more information.
 type AuthEvent struct {
 	ID        int             `db:"id"`
+	Channel   string          `db:"channel"`
 	Type      types.EventType `db:"event_type"`
 	Data      string          `db:"event_data"`
 	Timestamp time.Time       `db:"event_time"`
@@ -83,8 +286,28 @@ type AuthEvent struct {
 //
 // # Does not log whisper messages.
 type MessageEvent struct {
-	ID      int       `db:"id"`
-	Issuer  string    `db:"issuer"`
-	Content string    `db:"content"`
-	Sent    time.Time `db:"sent"`
+	ID        int       `db:"id"`
+	Channel   string    `db:"channel"`
+	MessageID string    `db:"message_id"` // Twitch's IRC message id, used to link CLEARMSG events
+	Issuer    string    `db:"issuer"`
+	Content   string    `db:"content"`
+	Sent      time.Time `db:"sent"`
+	Deleted   bool      `db:"deleted"`
+	// True if the bot (Gatekeeper) deleted the message, false if a human mod did.
+	DeletedByBot bool         `db:"deleted_by_bot"`
+	DeletedAt    sql.NullTime `db:"deleted_at"`
+}
+
+// Model for room state changes like slow mode, sub-only mode, emote-only mode, followers-only mode.
+//
+// Every row represents the full room state of a channel after a change.
+type RoomStateEvent struct {
+	ID            int       `db:"id"`
+	Channel       string    `db:"channel"`
+	EmoteOnly     bool      `db:"emote_only"`
+	FollowersOnly int       `db:"followers_only"`
+	R9K           bool      `db:"r9k"`
+	Slow          int       `db:"slow"`
+	SubsOnly      bool      `db:"subs_only"`
+	Changed       time.Time `db:"changed"`
 }
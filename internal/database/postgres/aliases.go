@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) AddCommandAlias(alias database.CommandAlias) error {
+	row := p.db.QueryRow(statements.AddAlias, alias.Channel, alias.Alias, alias.Command, alias.Added)
+
+	err := row.Scan(&alias.ID)
+
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") {
+			return sql.ErrTxDone
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (p *psql) DeleteCommandAlias(channel, alias string) error {
+	res, err := p.db.Exec(statements.DeleteAlias, channel, alias)
+	if err != nil {
+		return err
+	}
+
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (p *psql) GetAllCommandAliases(channel string) ([]database.CommandAlias, error) {
+	rows, err := p.db.Query(statements.GetAllAliases, channel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []database.CommandAlias{}
+
+	for rows.Next() {
+		a := database.CommandAlias{}
+
+		if err := rows.Scan(&a.ID, &a.Channel, &a.Alias, &a.Command, &a.Added); err != nil {
+			return nil, err
+		}
+
+		aliases = append(aliases, a)
+	}
+
+	return aliases, rows.Err()
+}
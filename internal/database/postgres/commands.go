@@ -11,8 +11,8 @@ import (
 )
 
 func (p *psql) AddTwitchCommand(command database.TwitchCommand) error {
-	row := p.db.QueryRow(statements.AddCommand, command.Name, command.Output, command.Userlevel,
-		command.Cooldown, command.Added, command.Edited)
+	row := p.db.QueryRow(statements.AddCommand, command.Channel, command.Name, command.Output, command.Userlevel,
+		command.Cooldown, command.UserCooldown, command.ModBypass, command.Cost, command.Added, command.Edited)
 
 	err := row.Scan(&command.ID)
 
@@ -26,8 +26,8 @@ func (p *psql) AddTwitchCommand(command database.TwitchCommand) error {
 	return nil
 }
 
-func (p *psql) GetAllTwitchCommands() ([]database.TwitchCommand, error) {
-	rows, err := p.db.Query(statements.GetAllCommands)
+func (p *psql) GetAllTwitchCommands(channel string) ([]database.TwitchCommand, error) {
+	rows, err := p.db.Query(statements.GetAllCommands, channel)
 	if err != nil {
 		return nil, err
 	}
@@ -37,8 +37,8 @@ func (p *psql) GetAllTwitchCommands() ([]database.TwitchCommand, error) {
 	for rows.Next() {
 		c := database.TwitchCommand{}
 
-		if err := rows.Scan(&c.ID, &c.Name, &c.Output, &c.Userlevel,
-			&c.Cooldown, &c.Added, &c.Edited); err != nil {
+		if err := rows.Scan(&c.ID, &c.Channel, &c.Name, &c.Output, &c.Userlevel,
+			&c.Cooldown, &c.UserCooldown, &c.ModBypass, &c.Cost, &c.Uses, &c.Added, &c.Edited); err != nil {
 			return nil, err
 		}
 
@@ -48,27 +48,46 @@ func (p *psql) GetAllTwitchCommands() ([]database.TwitchCommand, error) {
 	return commands, nil
 }
 
-func (p *psql) GetOneTwitchCommand(name string) (database.TwitchCommand, error) {
-	row := p.db.QueryRow(statements.GetCommand, name)
+func (p *psql) GetOneTwitchCommand(channel, name string) (database.TwitchCommand, error) {
+	row := p.db.QueryRow(statements.GetCommand, channel, name)
 
 	var c database.TwitchCommand
 
-	err := row.Scan(&c.ID, &c.Name, &c.Output, &c.Userlevel, &c.Cooldown, &c.Added, &c.Edited)
+	err := row.Scan(&c.ID, &c.Channel, &c.Name, &c.Output, &c.Userlevel, &c.Cooldown, &c.UserCooldown,
+		&c.ModBypass, &c.Cost, &c.Uses, &c.Added, &c.Edited)
 
 	return c, err
 }
 
+// Increments the usage counter of a command and returns the new count.
+func (p *psql) IncrementTwitchCommandUses(channel, name string) (int, error) {
+	row := p.db.QueryRow(statements.IncrementCommandUses, channel, name)
+
+	var uses int
+
+	err := row.Scan(&uses)
+
+	return uses, err
+}
+
 func (p *psql) UpdateTwitchCommand(command database.TwitchCommand) (database.TwitchCommand, error) {
 	row := p.db.QueryRow(statements.UpdateCommand, command.Output, command.Userlevel, command.Cooldown,
-		command.Edited.Time, command.Name)
+		command.UserCooldown, command.ModBypass, command.Cost, command.Edited.Time, command.Channel, command.Name)
 
 	err := row.Scan(&command.ID)
 
 	return command, err
 }
 
-func (p *psql) DeleteTwitchCommand(name string) error {
-	res, err := p.db.Exec(statements.DeleteCommand, name)
+// Deletes a command and all of its aliases.
+func (p *psql) DeleteTwitchCommand(channel, name string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(statements.DeleteCommand, channel, name)
 	if err != nil {
 		return err
 	}
@@ -83,5 +102,10 @@ func (p *psql) DeleteTwitchCommand(name string) error {
 	if aff > 0 && aff != 1 {
 		return fmt.Errorf("error: multiple rows affected (%d)", aff)
 	}
-	return nil
+
+	if _, err := tx.Exec(statements.DeleteAliasesOfCommand, channel, name); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) SetCommandCooldown(cooldown database.CommandCooldown) (database.CommandCooldown, error) {
+	row := p.db.QueryRow(statements.SetCooldown, cooldown.Channel, cooldown.Command, cooldown.Username,
+		cooldown.ExpiresAt)
+
+	err := row.Scan(&cooldown.ID)
+
+	return cooldown, err
+}
+
+func (p *psql) GetActiveCommandCooldowns(now time.Time) ([]database.CommandCooldown, error) {
+	rows, err := p.db.Query(statements.GetActiveCooldowns, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cooldowns := []database.CommandCooldown{}
+
+	for rows.Next() {
+		c := database.CommandCooldown{}
+
+		if err := rows.Scan(&c.ID, &c.Channel, &c.Command, &c.Username, &c.ExpiresAt); err != nil {
+			return nil, err
+		}
+
+		cooldowns = append(cooldowns, c)
+	}
+
+	return cooldowns, rows.Err()
+}
+
+func (p *psql) DeleteExpiredCommandCooldowns(now time.Time) error {
+	_, err := p.db.Exec(statements.DeleteExpiredCooldowns, now)
+	return err
+}
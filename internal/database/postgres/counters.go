@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) GetCounter(channel, name string) (database.Counter, error) {
+	row := p.db.QueryRow(statements.GetCounter, channel, name)
+
+	var c database.Counter
+
+	err := row.Scan(&c.ID, &c.Channel, &c.Name, &c.Value, &c.Updated)
+
+	return c, err
+}
+
+func (p *psql) SetCounter(channel, name string, value int) (database.Counter, error) {
+	row := p.db.QueryRow(statements.SetCounter, channel, name, value, time.Now())
+
+	var c database.Counter
+
+	err := row.Scan(&c.ID, &c.Channel, &c.Name, &c.Value, &c.Updated)
+
+	return c, err
+}
+
+func (p *psql) AdjustCounter(channel, name string, delta int) (database.Counter, error) {
+	row := p.db.QueryRow(statements.AdjustCounter, channel, name, delta, time.Now())
+
+	var c database.Counter
+
+	err := row.Scan(&c.ID, &c.Channel, &c.Name, &c.Value, &c.Updated)
+
+	return c, err
+}
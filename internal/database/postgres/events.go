@@ -6,7 +6,7 @@ import (
 )
 
 func (p *psql) AddAuthEvent(event database.AuthEvent) (database.AuthEvent, error) {
-	row := p.db.QueryRow(statements.AddEvent, event.Type, event.Data, event.Timestamp)
+	row := p.db.QueryRow(statements.AddEvent, event.Channel, event.Type, event.Data, event.Timestamp)
 
 	err := row.Scan(&event.ID)
 
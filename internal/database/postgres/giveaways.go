@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+)
+
+func (p *psql) AddGiveaway(giveaway database.Giveaway) (database.Giveaway, error) {
+	row := p.db.QueryRow(statements.AddGiveaway, giveaway.Channel, giveaway.Keyword, giveaway.FollowersOnly,
+		giveaway.SubsOnly, giveaway.MinMessages, giveaway.SeenBefore, giveaway.SubLuck, giveaway.StartedBy, giveaway.Started)
+
+	err := row.Scan(&giveaway.ID)
+
+	return giveaway, err
+}
+
+// Returns the giveaway of the channel which still accepts entries.
+func (p *psql) GetOpenGiveaway(channel string) (database.Giveaway, error) {
+	return scanGiveaway(p.db.QueryRow(statements.GetOpenGiveaway, channel))
+}
+
+// Returns the giveaway of the channel which was started last, open or closed.
+func (p *psql) GetLatestGiveaway(channel string) (database.Giveaway, error) {
+	return scanGiveaway(p.db.QueryRow(statements.GetLatestGiveaway, channel))
+}
+
+func (p *psql) CloseGiveaway(id int, closed time.Time) error {
+	res, err := p.db.Exec(statements.CloseGiveaway, id, closed)
+	if err != nil {
+		return err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		logging.WriteError(err)
+		return err
+	}
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	if aff > 0 && aff != 1 {
+		return fmt.Errorf("error: multiple rows affected (%d)", aff)
+	}
+	return nil
+}
+
+func (p *psql) AddGiveawayEntry(entry database.GiveawayEntry) error {
+	row := p.db.QueryRow(statements.AddGiveawayEntry, entry.GiveawayID, entry.Username, entry.DisplayName,
+		entry.Tickets, entry.Entered)
+
+	err := row.Scan(&entry.ID)
+
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") {
+			return sql.ErrTxDone
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (p *psql) GetGiveawayEntries(giveawayID int) ([]database.GiveawayEntry, error) {
+	rows, err := p.db.Query(statements.GetGiveawayEntries, giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []database.GiveawayEntry{}
+
+	for rows.Next() {
+		e := database.GiveawayEntry{}
+
+		if err := rows.Scan(&e.ID, &e.GiveawayID, &e.Username, &e.DisplayName, &e.Tickets, &e.Entered); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+func (p *psql) AddGiveawayDraw(draw database.GiveawayDraw) (database.GiveawayDraw, error) {
+	row := p.db.QueryRow(statements.AddGiveawayDraw, draw.GiveawayID, draw.Winner, draw.Seed, draw.Pool,
+		draw.Tickets, draw.Redraw, draw.DrawnBy, draw.Drawn)
+
+	err := row.Scan(&draw.ID)
+
+	return draw, err
+}
+
+func (p *psql) GetGiveawayDraws(giveawayID int) ([]database.GiveawayDraw, error) {
+	rows, err := p.db.Query(statements.GetGiveawayDraws, giveawayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	draws := []database.GiveawayDraw{}
+
+	for rows.Next() {
+		d := database.GiveawayDraw{}
+
+		if err := rows.Scan(&d.ID, &d.GiveawayID, &d.Winner, &d.Seed, &d.Pool, &d.Tickets, &d.Redraw,
+			&d.DrawnBy, &d.Drawn); err != nil {
+			return nil, err
+		}
+
+		draws = append(draws, d)
+	}
+
+	return draws, rows.Err()
+}
+
+// Scans a giveaway from a *sql.Row or *sql.Rows.
+func scanGiveaway(row interface{ Scan(...interface{}) error }) (database.Giveaway, error) {
+	var g database.Giveaway
+
+	err := row.Scan(&g.ID, &g.Channel, &g.Keyword, &g.FollowersOnly, &g.SubsOnly, &g.MinMessages, &g.SeenBefore,
+		&g.SubLuck, &g.StartedBy, &g.Started, &g.Closed)
+
+	return g, err
+}
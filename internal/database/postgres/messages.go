@@ -1,14 +1,44 @@
 package postgres
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/devusSs/twitch-kraken/internal/database"
 	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
 )
 
 func (p *psql) AddMessageEvent(event database.MessageEvent) (database.MessageEvent, error) {
-	row := p.db.QueryRow(statements.AddMessage, event.Issuer, event.Content, event.Sent)
+	row := p.db.QueryRow(statements.AddMessage, event.Channel, event.MessageID, event.Issuer, event.Content, event.Sent)
 
 	err := row.Scan(&event.ID)
 
 	return event, err
 }
+
+// Returns sql.ErrNoRows if there is no (not yet deleted) message with that id.
+func (p *psql) MarkMessageEventDeleted(messageID string, byBot bool, deleted time.Time) error {
+	res, err := p.db.Exec(statements.MarkMessageDeleted, messageID, byBot, deleted)
+	if err != nil {
+		return err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Returns how many chat messages a user sent in the channel.
+func (p *psql) CountMessageEvents(channel, issuer string) (int, error) {
+	row := p.db.QueryRow(statements.CountMessagesOfUser, channel, issuer)
+
+	var count int
+
+	err := row.Scan(&count)
+
+	return count, err
+}
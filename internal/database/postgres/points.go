@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) GetPoints(channel, username string) (database.Points, error) {
+	row := p.db.QueryRow(statements.GetPoints, channel, username)
+
+	var pts database.Points
+
+	err := row.Scan(&pts.ID, &pts.Channel, &pts.Username, &pts.Balance, &pts.Updated)
+
+	return pts, err
+}
+
+// Adds points to several users of a channel at once (keyed by username), either all or none are added.
+func (p *psql) AddPoints(channel string, amounts map[string]int) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	for username, amount := range amounts {
+		if _, err := tx.Exec(statements.AddPoints, channel, username, amount, now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Deducts points from a user, returns database.ErrInsufficientPoints if the balance is too low.
+func (p *psql) SpendPoints(channel, username string, amount int) (database.Points, error) {
+	row := p.db.QueryRow(statements.SpendPoints, channel, username, amount, time.Now())
+
+	var pts database.Points
+
+	err := row.Scan(&pts.ID, &pts.Channel, &pts.Username, &pts.Balance, &pts.Updated)
+	if err == sql.ErrNoRows {
+		return pts, database.ErrInsufficientPoints
+	}
+
+	return pts, err
+}
+
+// Moves points from one user to another, returns database.ErrInsufficientPoints if the sender's balance is too low.
+func (p *psql) TransferPoints(channel, from, to string, amount int) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	res, err := tx.Exec(statements.SpendPoints, channel, from, amount, now)
+	if err != nil {
+		return err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if aff == 0 {
+		return database.ErrInsufficientPoints
+	}
+
+	if _, err := tx.Exec(statements.AddPoints, channel, to, amount, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *psql) GetTopPoints(channel string, limit int) ([]database.Points, error) {
+	rows, err := p.db.Query(statements.GetTopPoints, channel, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	top := []database.Points{}
+
+	for rows.Next() {
+		pts := database.Points{}
+
+		if err := rows.Scan(&pts.ID, &pts.Channel, &pts.Username, &pts.Balance, &pts.Updated); err != nil {
+			return nil, err
+		}
+
+		top = append(top, pts)
+	}
+
+	return top, rows.Err()
+}
@@ -13,6 +13,8 @@ import (
 // Internal Postgres structure which executes database.Service layer functions.
 type psql struct {
 	db *sql.DB
+	// Channel which rows of older (single channel) versions get assigned to on migration.
+	defaultChannel string
 }
 
 // Inits a new Postgres connection and returns database.Service layer.
@@ -23,7 +25,12 @@ func New(cfg *config.Config) (database.Service, error) {
 
 	db, err := sql.Open("postgres", dsn)
 
-	return &psql{db}, err
+	defaultChannel := ""
+	if len(cfg.Twitch.Channels) > 0 {
+		defaultChannel = cfg.Twitch.Channels[0].Name
+	}
+
+	return &psql{db, defaultChannel}, err
 }
 
 // Test database connection.
@@ -48,11 +55,91 @@ func (p *psql) Migrate() error {
 		return err
 	}
 
+	_, err = p.db.Exec(statements.CreateViewerSessionsTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateViewerSessionsIndex)
+	if err != nil {
+		return err
+	}
+
 	_, err = p.db.Exec(statements.CreateCommandsTable)
 	if err != nil {
 		return err
 	}
 
+	_, err = p.db.Exec(statements.AlterCommandsTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateCommandAliasesTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateTimersTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.AlterTimersTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateQuotesTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateGiveawaysTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateGiveawayEntriesTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateGiveawayDrawsTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreatePointsTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateSongRequestsTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateSongHistoryTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateCountersTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateCommandCooldownsTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.AlterCommandCooldownsTable)
+	if err != nil {
+		return err
+	}
+
 	_, err = p.db.Exec(statements.CreateAuthEventsTable)
 	if err != nil {
 		return err
@@ -63,5 +150,53 @@ func (p *psql) Migrate() error {
 		return err
 	}
 
+	_, err = p.db.Exec(statements.AlterMessageEventsTable)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateMessageIDIndex)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateRoomStateEventsTable)
+	if err != nil {
+		return err
+	}
+
+	if err := p.migrateChannels(); err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.CreateMessageIssuerIndex)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(statements.StripCommandPrefixes)
+	return err
+}
+
+// Scopes tables of older (single channel) versions per channel.
+func (p *psql) migrateChannels() error {
+	for _, statement := range statements.AddChannelColumns {
+		if _, err := p.db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	for _, statement := range statements.AssignLegacyChannel {
+		if _, err := p.db.Exec(statement, p.defaultChannel); err != nil {
+			return err
+		}
+	}
+
+	for _, statement := range statements.CreateChannelIndexes {
+		if _, err := p.db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
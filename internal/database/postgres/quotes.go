@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+	"github.com/devusSs/twitch-kraken/internal/logging"
+)
+
+// Adds a quote and returns it with its number.
+func (p *psql) AddQuote(quote database.Quote) (database.Quote, error) {
+	row := p.db.QueryRow(statements.AddQuote, quote.Channel, quote.Text, quote.QuotedUser, quote.Author,
+		quote.Game, quote.StreamDate, quote.Added)
+
+	err := row.Scan(&quote.ID, &quote.Number)
+
+	if err != nil {
+		// Two quotes were added at the same time and got the same number.
+		if strings.Contains(err.Error(), "duplicate") {
+			return quote, sql.ErrTxDone
+		}
+		return quote, err
+	}
+
+	return quote, nil
+}
+
+func (p *psql) GetQuote(channel string, number int) (database.Quote, error) {
+	return scanQuote(p.db.QueryRow(statements.GetQuote, channel, number))
+}
+
+func (p *psql) GetRandomQuote(channel string) (database.Quote, error) {
+	return scanQuote(p.db.QueryRow(statements.GetRandomQuote, channel))
+}
+
+func (p *psql) SearchQuotes(channel, term string) ([]database.Quote, error) {
+	rows, err := p.db.Query(statements.SearchQuotes, channel, term)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotes := []database.Quote{}
+
+	for rows.Next() {
+		q, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		quotes = append(quotes, q)
+	}
+
+	return quotes, rows.Err()
+}
+
+func (p *psql) DeleteQuote(channel string, number int) error {
+	res, err := p.db.Exec(statements.DeleteQuote, channel, number)
+	if err != nil {
+		return err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		logging.WriteError(err)
+		return err
+	}
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	if aff > 0 && aff != 1 {
+		return fmt.Errorf("error: multiple rows affected (%d)", aff)
+	}
+	return nil
+}
+
+// Scans a quote from a *sql.Row or *sql.Rows.
+func scanQuote(row interface{ Scan(...interface{}) error }) (database.Quote, error) {
+	var q database.Quote
+
+	err := row.Scan(&q.ID, &q.Channel, &q.Number, &q.Text, &q.QuotedUser, &q.Author, &q.Game, &q.StreamDate, &q.Added)
+
+	return q, err
+}
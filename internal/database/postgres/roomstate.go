@@ -0,0 +1,15 @@
+package postgres
+
+import (
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) AddRoomStateEvent(event database.RoomStateEvent) (database.RoomStateEvent, error) {
+	row := p.db.QueryRow(statements.AddRoomState, event.Channel, event.EmoteOnly, event.FollowersOnly,
+		event.R9K, event.Slow, event.SubsOnly, event.Changed)
+
+	err := row.Scan(&event.ID)
+
+	return event, err
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) OpenViewerSession(channel, username string, joined time.Time) error {
+	_, err := p.db.Exec(statements.OpenViewerSession, channel, username, joined)
+	return err
+}
+
+func (p *psql) CloseViewerSession(channel, username string, parted time.Time) error {
+	_, err := p.db.Exec(statements.CloseViewerSession, channel, username, parted)
+	return err
+}
+
+// Closes every open session of the channel, e.g. when the bot leaves the channel.
+func (p *psql) CloseAllViewerSessions(channel string, parted time.Time) error {
+	_, err := p.db.Exec(statements.CloseAllViewerSessions, channel, parted)
+	return err
+}
+
+// Closes the sessions left open by a crash or lost connection at the time their users were seen last.
+func (p *psql) CloseStaleViewerSessions(channel string) error {
+	_, err := p.db.Exec(statements.CloseStaleViewerSessions, channel)
+	return err
+}
+
+// Returns the total watch time of a user, open sessions count until now.
+func (p *psql) GetWatchTime(channel, username string, now time.Time) (time.Duration, error) {
+	row := p.db.QueryRow(statements.GetWatchTime, channel, username, now)
+
+	var seconds float64
+
+	err := row.Scan(&seconds)
+
+	return time.Duration(seconds * float64(time.Second)), err
+}
+
+// Returns the usernames of everyone watching the channel right now.
+func (p *psql) GetOpenViewerSessions(channel string) ([]string, error) {
+	rows, err := p.db.Query(statements.GetOpenViewerSessions, channel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usernames := []string{}
+
+	for rows.Next() {
+		var username string
+
+		if err := rows.Scan(&username); err != nil {
+			return nil, err
+		}
+
+		usernames = append(usernames, username)
+	}
+
+	return usernames, rows.Err()
+}
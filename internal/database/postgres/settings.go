@@ -5,12 +5,12 @@ import (
 	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
 )
 
-func (p *psql) LoadGateKeeperSettings() (database.GateKeeperSettings, error) {
-	row := p.db.QueryRow(statements.GetGatekeeperSettings)
+func (p *psql) LoadGateKeeperSettings(channel string) (database.GateKeeperSettings, error) {
+	row := p.db.QueryRow(statements.GetGatekeeperSettings, channel)
 
 	var s database.GateKeeperSettings
 
-	err := row.Scan(&s.ID, &s.FilterChat, &s.FilterLinks,
+	err := row.Scan(&s.ID, &s.Channel, &s.FilterChat, &s.FilterLinks,
 		&s.IgnoreMods, &s.IgnoreSubs, &s.SymbolsMax, &s.EmotesMax, &s.BadWords,
 		&s.SetTime)
 
@@ -18,7 +18,7 @@ func (p *psql) LoadGateKeeperSettings() (database.GateKeeperSettings, error) {
 }
 
 func (p *psql) UpdateGateKeeperSettings(settings database.GateKeeperSettings) error {
-	row := p.db.QueryRow(statements.UpdateGatekeeperSettings, settings.FilterChat,
+	row := p.db.QueryRow(statements.UpdateGatekeeperSettings, settings.Channel, settings.FilterChat,
 		settings.FilterLinks, settings.IgnoreMods, settings.IgnoreSubs,
 		settings.SymbolsMax, settings.EmotesMax, settings.BadWords,
 		settings.SetTime)
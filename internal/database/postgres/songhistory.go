@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) AddSongHistory(song database.SongHistory) error {
+	row := p.db.QueryRow(statements.AddSongHistory, song.Channel, song.TrackID, song.Title, song.Artists, song.URL,
+		song.Started, song.StreamStarted)
+
+	return row.Scan(&song.ID)
+}
+
+// Returns the track which was playing at the specified time of the stream which started at streamStarted.
+func (p *psql) GetSongAt(channel string, streamStarted, at time.Time) (database.SongHistory, error) {
+	return scanSongHistory(p.db.QueryRow(statements.GetSongAt, channel, streamStarted, at))
+}
+
+// Returns every track of the stream which started at streamStarted, in the order they were played.
+func (p *psql) GetStreamSongs(channel string, streamStarted time.Time) ([]database.SongHistory, error) {
+	rows, err := p.db.Query(statements.GetStreamSongs, channel, streamStarted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := []database.SongHistory{}
+
+	for rows.Next() {
+		song, err := scanSongHistory(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		songs = append(songs, song)
+	}
+
+	return songs, rows.Err()
+}
+
+// Returns the start of the last stream which has tracks in the song history.
+func (p *psql) GetLastStreamStart(channel string) (time.Time, error) {
+	row := p.db.QueryRow(statements.GetLastStreamStart, channel)
+
+	var started time.Time
+
+	err := row.Scan(&started)
+
+	return started, err
+}
+
+// Scans a song history entry from a *sql.Row or *sql.Rows.
+func scanSongHistory(row interface{ Scan(...interface{}) error }) (database.SongHistory, error) {
+	var s database.SongHistory
+
+	err := row.Scan(&s.ID, &s.Channel, &s.TrackID, &s.Title, &s.Artists, &s.URL, &s.Started, &s.StreamStarted)
+
+	return s, err
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) AddSongRequest(request database.SongRequest) (database.SongRequest, error) {
+	row := p.db.QueryRow(statements.AddSongRequest, request.Channel, request.Username, request.TrackID, request.TrackURI,
+		request.Title, request.Artists, request.DurationMS, request.Requested)
+
+	err := row.Scan(&request.ID)
+
+	return request, err
+}
+
+// Returns the requests waiting in the bot's queue, oldest first.
+func (p *psql) GetPendingSongRequests(channel string) ([]database.SongRequest, error) {
+	rows, err := p.db.Query(statements.GetPendingSongRequests, channel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []database.SongRequest{}
+
+	for rows.Next() {
+		r := database.SongRequest{}
+
+		if err := rows.Scan(&r.ID, &r.Channel, &r.Username, &r.TrackID, &r.TrackURI, &r.Title, &r.Artists,
+			&r.DurationMS, &r.Status, &r.Requested, &r.Queued); err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, r)
+	}
+
+	return requests, rows.Err()
+}
+
+// Marks a pending request as added to the Spotify queue, sql.ErrNoRows if it is not pending anymore.
+func (p *psql) MarkSongRequestQueued(id int, queued time.Time) error {
+	res, err := p.db.Exec(statements.MarkSongRequestQueued, id, queued)
+	if err != nil {
+		return err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *psql) MarkSongRequestSkipped(channel, trackID string) error {
+	_, err := p.db.Exec(statements.MarkSongRequestSkipped, channel, trackID)
+	return err
+}
+
+// Clears every pending request of the channel and returns how many were cleared.
+func (p *psql) ClearSongRequests(channel string) (int, error) {
+	res, err := p.db.Exec(statements.ClearSongRequests, channel)
+	if err != nil {
+		return 0, err
+	}
+	aff, err := res.RowsAffected()
+	return int(aff), err
+}
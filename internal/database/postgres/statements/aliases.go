@@ -0,0 +1,30 @@
+package statements
+
+const (
+	CreateCommandAliasesTable = `
+		CREATE TABLE IF NOT EXISTS command_aliases (
+			id bigserial,
+			channel text NOT NULL,
+			alias text NOT NULL,
+			command text NOT NULL,
+			added timestamp NOT NULL,
+			UNIQUE (channel, alias)
+		);
+	`
+
+	AddAlias = `
+		INSERT INTO command_aliases (channel, alias, command, added) VALUES ($1, $2, $3, $4) RETURNING id;
+	`
+
+	DeleteAlias = `
+		DELETE FROM command_aliases WHERE channel = $1 AND alias = $2;
+	`
+
+	DeleteAliasesOfCommand = `
+		DELETE FROM command_aliases WHERE channel = $1 AND command = $2;
+	`
+
+	GetAllAliases = `
+		SELECT id, channel, alias, command, added FROM command_aliases WHERE channel = $1 ORDER BY alias;
+	`
+)
@@ -2,25 +2,43 @@ package statements
 
 const (
 	AddCommand = `
-		INSERT INTO twitch_commands (name, output, userlevel, cooldown, added, edited) 
-		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;
+		INSERT INTO twitch_commands (channel, name, output, userlevel, cooldown, user_cooldown, mod_bypass, cost, added, edited) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id;
 	`
 
 	UpdateCommand = `
-		UPDATE twitch_commands SET output = $1, userlevel = $2, cooldown = $3, edited = $4 
-		WHERE name = $5 
+		UPDATE twitch_commands SET output = $1, userlevel = $2, cooldown = $3, user_cooldown = $4, mod_bypass = $5, cost = $6, edited = $7 
+		WHERE channel = $8 AND name = $9 
 		RETURNING id;
 	`
 
 	DeleteCommand = `
-		DELETE FROM twitch_commands WHERE name = $1;
+		DELETE FROM twitch_commands WHERE channel = $1 AND name = $2;
 	`
 
 	GetAllCommands = `
-		SELECT * FROM twitch_commands;
+		SELECT id, channel, name, output, userlevel, cooldown, user_cooldown, mod_bypass, cost, uses, added, edited 
+		FROM twitch_commands WHERE channel = $1;
 	`
 
+	// Single statement, so concurrent calls can not lose an increment.
+	IncrementCommandUses = `
+		UPDATE twitch_commands SET uses = uses + 1 WHERE channel = $1 AND name = $2 RETURNING uses;
+	`
+
+	// Resolves aliases, $2 may be the name of the command or one of its aliases.
 	GetCommand = `
-		SELECT * FROM twitch_commands WHERE name = $1;
+		SELECT id, channel, name, output, userlevel, cooldown, user_cooldown, mod_bypass, cost, uses, added, edited 
+		FROM twitch_commands WHERE channel = $1 AND name = COALESCE(
+			(SELECT command FROM command_aliases WHERE channel = $1 AND alias = $2), $2
+		);
 	`
 )
+
+// Older versions stored command names with the (hardcoded) "!" prefix, names are stored without prefix now.
+const StripCommandPrefixes = `
+	UPDATE twitch_commands c SET name = substr(c.name, 2) 
+	WHERE c.name LIKE '!%' AND NOT EXISTS (
+		SELECT 1 FROM twitch_commands o WHERE o.channel = c.channel AND o.name = substr(c.name, 2)
+	);
+`
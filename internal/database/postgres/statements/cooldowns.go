@@ -0,0 +1,35 @@
+package statements
+
+const (
+	CreateCommandCooldownsTable = `
+		CREATE TABLE IF NOT EXISTS command_cooldowns (
+			id bigserial,
+			channel text NOT NULL,
+			command text NOT NULL,
+			username text NOT NULL DEFAULT '',
+			expires_at timestamptz NOT NULL,
+			UNIQUE (channel, command, username)
+		);
+	`
+
+	// Cooldowns are compared with the current time, tables created by older versions stored them without time zone.
+	AlterCommandCooldownsTable = `
+		ALTER TABLE command_cooldowns ALTER COLUMN expires_at TYPE timestamptz;
+	`
+
+	SetCooldown = `
+		INSERT INTO command_cooldowns (channel, command, username, expires_at) 
+		VALUES ($1, $2, $3, $4) 
+		ON CONFLICT (channel, command, username) DO UPDATE SET expires_at = EXCLUDED.expires_at 
+		RETURNING id;
+	`
+
+	GetActiveCooldowns = `
+		SELECT id, channel, command, username, expires_at 
+		FROM command_cooldowns WHERE expires_at > $1;
+	`
+
+	DeleteExpiredCooldowns = `
+		DELETE FROM command_cooldowns WHERE expires_at <= $1;
+	`
+)
@@ -0,0 +1,20 @@
+package statements
+
+const (
+	GetCounter = `
+		SELECT id, channel, name, value, updated FROM counters WHERE channel = $1 AND name = $2;
+	`
+
+	SetCounter = `
+		INSERT INTO counters (channel, name, value, updated) VALUES ($1, $2, $3, $4) 
+		ON CONFLICT (channel, name) DO UPDATE SET value = EXCLUDED.value, updated = EXCLUDED.updated 
+		RETURNING id, channel, name, value, updated;
+	`
+
+	// Adds $3 to the counter (creates it if needed) in a single statement, so concurrent calls can not lose an update.
+	AdjustCounter = `
+		INSERT INTO counters (channel, name, value, updated) VALUES ($1, $2, $3, $4) 
+		ON CONFLICT (channel, name) DO UPDATE SET value = counters.value + EXCLUDED.value, updated = EXCLUDED.updated 
+		RETURNING id, channel, name, value, updated;
+	`
+)
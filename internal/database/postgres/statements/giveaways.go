@@ -0,0 +1,85 @@
+package statements
+
+const (
+	CreateGiveawaysTable = `
+		CREATE TABLE IF NOT EXISTS giveaways (
+			id bigserial PRIMARY KEY,
+			channel text NOT NULL,
+			keyword text NOT NULL,
+			followers_only boolean NOT NULL DEFAULT FALSE,
+			subs_only boolean NOT NULL DEFAULT FALSE,
+			min_messages integer NOT NULL DEFAULT 0,
+			seen_before timestamp,
+			sub_luck integer NOT NULL DEFAULT 1,
+			started_by text NOT NULL,
+			started timestamp NOT NULL,
+			closed timestamp
+		);
+	`
+
+	CreateGiveawayEntriesTable = `
+		CREATE TABLE IF NOT EXISTS giveaway_entries (
+			id bigserial,
+			giveaway_id bigint NOT NULL REFERENCES giveaways (id) ON DELETE CASCADE,
+			username text NOT NULL,
+			displayname text NOT NULL,
+			tickets integer NOT NULL,
+			entered timestamp NOT NULL,
+			UNIQUE (giveaway_id, username)
+		);
+	`
+
+	// Every draw is kept, so a winner can be verified with the seed and the pool afterwards.
+	CreateGiveawayDrawsTable = `
+		CREATE TABLE IF NOT EXISTS giveaway_draws (
+			id bigserial,
+			giveaway_id bigint NOT NULL REFERENCES giveaways (id) ON DELETE CASCADE,
+			winner text NOT NULL,
+			seed bigint NOT NULL,
+			pool text NOT NULL,
+			tickets integer NOT NULL,
+			redraw boolean NOT NULL DEFAULT FALSE,
+			drawn_by text NOT NULL,
+			drawn timestamp NOT NULL
+		);
+	`
+
+	AddGiveaway = `
+		INSERT INTO giveaways (channel, keyword, followers_only, subs_only, min_messages, seen_before, sub_luck, started_by, started) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;
+	`
+
+	GetOpenGiveaway = `
+		SELECT id, channel, keyword, followers_only, subs_only, min_messages, seen_before, sub_luck, started_by, started, closed 
+		FROM giveaways WHERE channel = $1 AND closed IS NULL ORDER BY id DESC LIMIT 1;
+	`
+
+	GetLatestGiveaway = `
+		SELECT id, channel, keyword, followers_only, subs_only, min_messages, seen_before, sub_luck, started_by, started, closed 
+		FROM giveaways WHERE channel = $1 ORDER BY id DESC LIMIT 1;
+	`
+
+	CloseGiveaway = `
+		UPDATE giveaways SET closed = $2 WHERE id = $1 AND closed IS NULL;
+	`
+
+	AddGiveawayEntry = `
+		INSERT INTO giveaway_entries (giveaway_id, username, displayname, tickets, entered) 
+		VALUES ($1, $2, $3, $4, $5) RETURNING id;
+	`
+
+	GetGiveawayEntries = `
+		SELECT id, giveaway_id, username, displayname, tickets, entered 
+		FROM giveaway_entries WHERE giveaway_id = $1 ORDER BY id;
+	`
+
+	AddGiveawayDraw = `
+		INSERT INTO giveaway_draws (giveaway_id, winner, seed, pool, tickets, redraw, drawn_by, drawn) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;
+	`
+
+	GetGiveawayDraws = `
+		SELECT id, giveaway_id, winner, seed, pool, tickets, redraw, drawn_by, drawn 
+		FROM giveaway_draws WHERE giveaway_id = $1 ORDER BY id;
+	`
+)
@@ -3,13 +3,27 @@ package statements
 const (
 	AddMessage = `
 		INSERT INTO message_events (
+			channel,
+			message_id,
 			issuer,
 			content,
 			sent
 		) VALUES (
 			$1,
 			$2,
-			$3
+			$3,
+			$4,
+			$5
 		) RETURNING id;
 	`
+
+	// Does not overwrite messages which have already been marked as deleted (by the bot).
+	MarkMessageDeleted = `
+		UPDATE message_events SET deleted = TRUE, deleted_by_bot = $2, deleted_at = $3 
+		WHERE message_id = $1 AND deleted = FALSE;
+	`
+
+	CountMessagesOfUser = `
+		SELECT COUNT(*) FROM message_events WHERE channel = $1 AND issuer = $2;
+	`
 )
@@ -0,0 +1,36 @@
+package statements
+
+const (
+	CreatePointsTable = `
+		CREATE TABLE IF NOT EXISTS points (
+			id bigserial,
+			channel text NOT NULL,
+			username text NOT NULL,
+			balance bigint NOT NULL DEFAULT 0 CHECK (balance >= 0),
+			updated timestamp NOT NULL,
+			UNIQUE (channel, username)
+		);
+	`
+
+	GetPoints = `
+		SELECT id, channel, username, balance, updated FROM points WHERE channel = $1 AND username = $2;
+	`
+
+	// Adds $3 (may be negative) to the balance, balances do not drop below 0.
+	AddPoints = `
+		INSERT INTO points (channel, username, balance, updated) VALUES ($1, $2, GREATEST($3, 0), $4) 
+		ON CONFLICT (channel, username) DO UPDATE SET balance = GREATEST(points.balance + $3, 0), updated = EXCLUDED.updated;
+	`
+
+	// Only updates the balance if it covers $3, so the check and the deduction can not be split by another call.
+	SpendPoints = `
+		UPDATE points SET balance = balance - $3, updated = $4 
+		WHERE channel = $1 AND username = $2 AND balance >= $3 
+		RETURNING id, channel, username, balance, updated;
+	`
+
+	GetTopPoints = `
+		SELECT id, channel, username, balance, updated FROM points 
+		WHERE channel = $1 AND balance > 0 ORDER BY balance DESC, username LIMIT $2;
+	`
+)
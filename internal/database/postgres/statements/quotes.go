@@ -0,0 +1,47 @@
+package statements
+
+const (
+	CreateQuotesTable = `
+		CREATE TABLE IF NOT EXISTS quotes (
+			id bigserial,
+			channel text NOT NULL,
+			number integer NOT NULL,
+			text text NOT NULL,
+			quoted_user text NOT NULL,
+			author text NOT NULL,
+			game text NOT NULL DEFAULT '',
+			stream_date timestamp,
+			added timestamp NOT NULL,
+			UNIQUE (channel, number)
+		);
+	`
+
+	// Quotes are numbered per channel, deleted numbers are not reused unless they were the highest.
+	AddQuote = `
+		INSERT INTO quotes (channel, number, text, quoted_user, author, game, stream_date, added) 
+		SELECT $1, COALESCE(MAX(number), 0) + 1, $2, $3, $4, $5, $6, $7 FROM quotes WHERE channel = $1 
+		RETURNING id, number;
+	`
+
+	GetQuote = `
+		SELECT id, channel, number, text, quoted_user, author, game, stream_date, added 
+		FROM quotes WHERE channel = $1 AND number = $2;
+	`
+
+	GetRandomQuote = `
+		SELECT id, channel, number, text, quoted_user, author, game, stream_date, added 
+		FROM quotes WHERE channel = $1 ORDER BY random() LIMIT 1;
+	`
+
+	// Case insensitive search in text, quoted user and game. strpos() needs no escaping of % and _ like LIKE.
+	SearchQuotes = `
+		SELECT id, channel, number, text, quoted_user, author, game, stream_date, added 
+		FROM quotes WHERE channel = $1 AND (strpos(lower(text), lower($2)) > 0 
+		OR strpos(lower(quoted_user), lower($2)) > 0 OR strpos(lower(game), lower($2)) > 0) 
+		ORDER BY number;
+	`
+
+	DeleteQuote = `
+		DELETE FROM quotes WHERE channel = $1 AND number = $2;
+	`
+)
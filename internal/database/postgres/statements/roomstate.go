@@ -0,0 +1,23 @@
+package statements
+
+const (
+	AddRoomState = `
+		INSERT INTO room_state_events (
+			channel,
+			emote_only,
+			followers_only,
+			r9k,
+			slow,
+			subs_only,
+			changed
+		) VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7
+		) RETURNING id;
+	`
+)
@@ -0,0 +1,49 @@
+package statements
+
+const (
+	CreateViewerSessionsTable = `
+		CREATE TABLE IF NOT EXISTS viewer_sessions (
+			id bigserial,
+			channel text NOT NULL,
+			username text NOT NULL,
+			joined timestamp NOT NULL,
+			parted timestamp
+		);
+	`
+
+	// Every user has at most one open session per channel.
+	CreateViewerSessionsIndex = `
+		CREATE UNIQUE INDEX IF NOT EXISTS viewer_sessions_open_idx ON viewer_sessions (channel, username) WHERE parted IS NULL;
+	`
+
+	// Does nothing if the user already has an open session, e.g. if Twitch sent a JOIN twice.
+	OpenViewerSession = `
+		INSERT INTO viewer_sessions (channel, username, joined) VALUES ($1, $2, $3) 
+		ON CONFLICT (channel, username) WHERE parted IS NULL DO NOTHING;
+	`
+
+	CloseViewerSession = `
+		UPDATE viewer_sessions SET parted = $3 WHERE channel = $1 AND username = $2 AND parted IS NULL;
+	`
+
+	CloseAllViewerSessions = `
+		UPDATE viewer_sessions SET parted = $2 WHERE channel = $1 AND parted IS NULL;
+	`
+
+	// Closes sessions the bot could not close (crash, lost connection) at the time the user was seen last.
+	CloseStaleViewerSessions = `
+		UPDATE viewer_sessions s SET parted = GREATEST(s.joined, COALESCE(
+			(SELECT u.lastseen FROM twitch_users u WHERE u.channel = s.channel AND u.twitchusername = s.username), s.joined)) 
+		WHERE s.channel = $1 AND s.parted IS NULL;
+	`
+
+	GetOpenViewerSessions = `
+		SELECT username FROM viewer_sessions WHERE channel = $1 AND parted IS NULL;
+	`
+
+	// Sums up all sessions of a user in seconds, open sessions count until $3.
+	GetWatchTime = `
+		SELECT COALESCE(EXTRACT(EPOCH FROM SUM(COALESCE(parted, $3) - joined)), 0) 
+		FROM viewer_sessions WHERE channel = $1 AND username = $2;
+	`
+)
@@ -2,11 +2,13 @@ package statements
 
 const (
 	GetGatekeeperSettings = `
-		SELECT * FROM gatekeeper_settings ORDER BY id DESC LIMIT 1;
+		SELECT id, channel, filter_chat, filter_links, ignore_mods, ignore_subs, symbols_max, emotes_max, bad_words, set 
+		FROM gatekeeper_settings WHERE channel = $1 ORDER BY id DESC LIMIT 1;
 	`
 
 	UpdateGatekeeperSettings = `
 		INSERT INTO gatekeeper_settings (
+			channel, 
 			filter_chat, 
 			filter_links, 
 			ignore_mods, 
@@ -23,7 +25,8 @@ const (
 			$5, 
 			$6, 
 			$7, 
-			$8
+			$8, 
+			$9
 		) RETURNING id;
 	`
 )
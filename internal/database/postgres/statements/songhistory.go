@@ -0,0 +1,38 @@
+package statements
+
+const (
+	CreateSongHistoryTable = `
+		CREATE TABLE IF NOT EXISTS song_history (
+			id bigserial,
+			channel text NOT NULL,
+			track_id text NOT NULL,
+			title text NOT NULL,
+			artists text NOT NULL,
+			url text NOT NULL DEFAULT '',
+			started timestamp NOT NULL,
+			stream_started timestamp
+		);
+	`
+
+	AddSongHistory = `
+		INSERT INTO song_history (channel, track_id, title, artists, url, started, stream_started) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;
+	`
+
+	// Returns the track which was playing at $3 during the stream started at $2.
+	GetSongAt = `
+		SELECT id, channel, track_id, title, artists, url, started, stream_started 
+		FROM song_history WHERE channel = $1 AND stream_started = $2 AND started <= $3 
+		ORDER BY started DESC LIMIT 1;
+	`
+
+	GetStreamSongs = `
+		SELECT id, channel, track_id, title, artists, url, started, stream_started 
+		FROM song_history WHERE channel = $1 AND stream_started = $2 ORDER BY started;
+	`
+
+	GetLastStreamStart = `
+		SELECT stream_started FROM song_history WHERE channel = $1 AND stream_started IS NOT NULL 
+		ORDER BY stream_started DESC LIMIT 1;
+	`
+)
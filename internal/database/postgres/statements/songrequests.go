@@ -0,0 +1,46 @@
+package statements
+
+const (
+	// Status is one of pending (waiting in the bot's queue), queued (added to the Spotify queue),
+	// skipped (skipped via !skip) or cleared (removed via !srclear before it was queued).
+	CreateSongRequestsTable = `
+		CREATE TABLE IF NOT EXISTS song_requests (
+			id bigserial,
+			channel text NOT NULL,
+			username text NOT NULL,
+			track_id text NOT NULL,
+			track_uri text NOT NULL,
+			title text NOT NULL,
+			artists text NOT NULL,
+			duration_ms integer NOT NULL,
+			status text NOT NULL DEFAULT 'pending',
+			requested timestamp NOT NULL,
+			queued timestamp
+		);
+	`
+
+	AddSongRequest = `
+		INSERT INTO song_requests (channel, username, track_id, track_uri, title, artists, duration_ms, requested) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;
+	`
+
+	GetPendingSongRequests = `
+		SELECT id, channel, username, track_id, track_uri, title, artists, duration_ms, status, requested, queued 
+		FROM song_requests WHERE channel = $1 AND status = 'pending' ORDER BY id;
+	`
+
+	MarkSongRequestQueued = `
+		UPDATE song_requests SET status = 'queued', queued = $2 WHERE id = $1 AND status = 'pending';
+	`
+
+	// Marks the latest queued request of the track as skipped.
+	MarkSongRequestSkipped = `
+		UPDATE song_requests SET status = 'skipped' WHERE id = (
+			SELECT id FROM song_requests WHERE channel = $1 AND track_id = $2 AND status = 'queued' ORDER BY id DESC LIMIT 1
+		);
+	`
+
+	ClearSongRequests = `
+		UPDATE song_requests SET status = 'cleared' WHERE channel = $1 AND status = 'pending';
+	`
+)
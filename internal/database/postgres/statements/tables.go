@@ -4,6 +4,7 @@ const (
 	CreateGateKeeperSettingsStore = `
 		CREATE TABLE IF NOT EXISTS gatekeeper_settings (
 			id bigserial, 
+			channel text NOT NULL DEFAULT '', 
 			filter_chat boolean DEFAULT TRUE, 
 			filter_links boolean DEFAULT TRUE, 
 			ignore_mods boolean DEFAULT TRUE, 
@@ -18,8 +19,9 @@ const (
 	CreateUsersTable = `
 		CREATE TABLE IF NOT EXISTS twitch_users (
 			id bigserial,
-			twitchid text UNIQUE,
-			twitchusername text NOT NULL UNIQUE,
+			channel text NOT NULL DEFAULT '',
+			twitchid text,
+			twitchusername text NOT NULL,
 			displayname text,
 			ismod boolean,
 			firstseen timestamp,
@@ -32,10 +34,14 @@ const (
 	CreateCommandsTable = `
 		CREATE TABLE IF NOT EXISTS twitch_commands (
 			id bigserial,
-			name text NOT NULL UNIQUE,
+			channel text NOT NULL DEFAULT '',
+			name text NOT NULL,
 			output text NOT NULL,
 			userlevel integer NOT NULL,
 			cooldown integer NOT NULL,
+			user_cooldown integer NOT NULL DEFAULT 0,
+			mod_bypass boolean NOT NULL DEFAULT FALSE,
+			uses bigint NOT NULL DEFAULT 0,
 			added timestamp,
 			edited timestamp
 		);
@@ -44,6 +50,7 @@ const (
 	CreateAuthEventsTable = `
 		CREATE TABLE IF NOT EXISTS auth_events (
 			id bigserial,
+			channel text NOT NULL DEFAULT '',
 			event_type text NOT NULL,
 			event_data text NOT NULL,
 			event_time timestamp NOT NULL
@@ -53,9 +60,98 @@ const (
 	CreateMessageEventsTable = `
 		CREATE TABLE IF NOT EXISTS message_events (
 			id bigserial,
+			channel text NOT NULL DEFAULT '',
+			message_id text,
 			issuer text NOT NULL,
 			content text NOT NULL,
-			sent timestamp NOT NULL
+			sent timestamp NOT NULL,
+			deleted boolean DEFAULT FALSE,
+			deleted_by_bot boolean DEFAULT FALSE,
+			deleted_at timestamp
 		);
 	`
+
+	// Adds the deletion tracking columns to message_events tables created by older versions.
+	AlterMessageEventsTable = `
+		ALTER TABLE message_events 
+		ADD COLUMN IF NOT EXISTS message_id text, 
+		ADD COLUMN IF NOT EXISTS deleted boolean DEFAULT FALSE, 
+		ADD COLUMN IF NOT EXISTS deleted_by_bot boolean DEFAULT FALSE, 
+		ADD COLUMN IF NOT EXISTS deleted_at timestamp;
+	`
+
+	// Deleted messages are looked up by their Twitch message id.
+	CreateMessageIDIndex = `
+		CREATE INDEX IF NOT EXISTS message_events_message_id_idx ON message_events (message_id);
+	`
+
+	// Giveaways with a minimum message count count the messages of every entrant.
+	//
+	// Needs the channel column, so it is created after older tables were scoped per channel.
+	CreateMessageIssuerIndex = `
+		CREATE INDEX IF NOT EXISTS message_events_channel_issuer_idx ON message_events (channel, issuer);
+	`
+
+	// Adds the per user cooldown, usage counter and cost columns to twitch_commands tables created by older versions.
+	AlterCommandsTable = `
+		ALTER TABLE twitch_commands 
+		ADD COLUMN IF NOT EXISTS user_cooldown integer NOT NULL DEFAULT 0, 
+		ADD COLUMN IF NOT EXISTS mod_bypass boolean NOT NULL DEFAULT FALSE, 
+		ADD COLUMN IF NOT EXISTS uses bigint NOT NULL DEFAULT 0, 
+		ADD COLUMN IF NOT EXISTS cost bigint NOT NULL DEFAULT 0;
+	`
+
+	CreateCountersTable = `
+		CREATE TABLE IF NOT EXISTS counters (
+			id bigserial,
+			channel text NOT NULL,
+			name text NOT NULL,
+			value bigint NOT NULL DEFAULT 0,
+			updated timestamp NOT NULL,
+			UNIQUE (channel, name)
+		);
+	`
+
+	CreateRoomStateEventsTable = `
+		CREATE TABLE IF NOT EXISTS room_state_events (
+			id bigserial,
+			channel text NOT NULL,
+			emote_only boolean NOT NULL,
+			followers_only integer NOT NULL,
+			r9k boolean NOT NULL,
+			slow integer NOT NULL,
+			subs_only boolean NOT NULL,
+			changed timestamp NOT NULL
+		);
+	`
+)
+
+// Statements to scope tables created by older (single channel) versions per channel.
+var (
+	AddChannelColumns = []string{
+		`ALTER TABLE gatekeeper_settings ADD COLUMN IF NOT EXISTS channel text NOT NULL DEFAULT '';`,
+		`ALTER TABLE twitch_users ADD COLUMN IF NOT EXISTS channel text NOT NULL DEFAULT '';`,
+		`ALTER TABLE twitch_commands ADD COLUMN IF NOT EXISTS channel text NOT NULL DEFAULT '';`,
+		`ALTER TABLE auth_events ADD COLUMN IF NOT EXISTS channel text NOT NULL DEFAULT '';`,
+		`ALTER TABLE message_events ADD COLUMN IF NOT EXISTS channel text NOT NULL DEFAULT '';`,
+
+		// Usernames and command names used to be unique globally, now they are unique per channel.
+		`ALTER TABLE twitch_users DROP CONSTRAINT IF EXISTS twitch_users_twitchid_key;`,
+		`ALTER TABLE twitch_users DROP CONSTRAINT IF EXISTS twitch_users_twitchusername_key;`,
+		`ALTER TABLE twitch_commands DROP CONSTRAINT IF EXISTS twitch_commands_name_key;`,
+	}
+
+	// Rows without a channel get assigned to the first configured channel ($1).
+	AssignLegacyChannel = []string{
+		`UPDATE gatekeeper_settings SET channel = $1 WHERE channel = '';`,
+		`UPDATE twitch_users SET channel = $1 WHERE channel = '';`,
+		`UPDATE twitch_commands SET channel = $1 WHERE channel = '';`,
+		`UPDATE auth_events SET channel = $1 WHERE channel = '';`,
+		`UPDATE message_events SET channel = $1 WHERE channel = '';`,
+	}
+
+	CreateChannelIndexes = []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS twitch_users_channel_username_idx ON twitch_users (channel, twitchusername);`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS twitch_commands_channel_name_idx ON twitch_commands (channel, name);`,
+	}
 )
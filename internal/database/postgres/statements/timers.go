@@ -0,0 +1,48 @@
+package statements
+
+const (
+	CreateTimersTable = `
+		CREATE TABLE IF NOT EXISTS timers (
+			id bigserial,
+			channel text NOT NULL,
+			name text NOT NULL,
+			message text NOT NULL,
+			interval_minutes integer NOT NULL,
+			min_lines integer NOT NULL,
+			enabled boolean NOT NULL DEFAULT TRUE,
+			added timestamptz NOT NULL,
+			edited timestamptz,
+			UNIQUE (channel, name)
+		);
+	`
+
+	// The scheduler compares added with the current time, tables created by older versions stored it without time zone.
+	AlterTimersTable = `
+		ALTER TABLE timers ALTER COLUMN added TYPE timestamptz, ALTER COLUMN edited TYPE timestamptz;
+	`
+
+	AddTimer = `
+		INSERT INTO timers (channel, name, message, interval_minutes, min_lines, enabled, added) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;
+	`
+
+	UpdateTimer = `
+		UPDATE timers SET message = $1, interval_minutes = $2, min_lines = $3, enabled = $4, edited = $5 
+		WHERE channel = $6 AND name = $7 
+		RETURNING id;
+	`
+
+	DeleteTimer = `
+		DELETE FROM timers WHERE channel = $1 AND name = $2;
+	`
+
+	GetAllTimers = `
+		SELECT id, channel, name, message, interval_minutes, min_lines, enabled, added, edited 
+		FROM timers WHERE channel = $1 ORDER BY id;
+	`
+
+	GetTimer = `
+		SELECT id, channel, name, message, interval_minutes, min_lines, enabled, added, edited 
+		FROM timers WHERE channel = $1 AND name = $2;
+	`
+)
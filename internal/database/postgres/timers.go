@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/devusSs/twitch-kraken/internal/database"
+	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
+)
+
+func (p *psql) AddTimer(timer database.Timer) error {
+	row := p.db.QueryRow(statements.AddTimer, timer.Channel, timer.Name, timer.Message, timer.Interval,
+		timer.MinLines, timer.Enabled, timer.Added)
+
+	err := row.Scan(&timer.ID)
+
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") {
+			return sql.ErrTxDone
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (p *psql) UpdateTimer(timer database.Timer) (database.Timer, error) {
+	row := p.db.QueryRow(statements.UpdateTimer, timer.Message, timer.Interval, timer.MinLines, timer.Enabled,
+		timer.Edited.Time, timer.Channel, timer.Name)
+
+	err := row.Scan(&timer.ID)
+
+	return timer, err
+}
+
+func (p *psql) DeleteTimer(channel, name string) error {
+	res, err := p.db.Exec(statements.DeleteTimer, channel, name)
+	if err != nil {
+		return err
+	}
+
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if aff == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (p *psql) GetAllTimers(channel string) ([]database.Timer, error) {
+	rows, err := p.db.Query(statements.GetAllTimers, channel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timers := []database.Timer{}
+
+	for rows.Next() {
+		t := database.Timer{}
+
+		if err := rows.Scan(&t.ID, &t.Channel, &t.Name, &t.Message, &t.Interval, &t.MinLines, &t.Enabled,
+			&t.Added, &t.Edited); err != nil {
+			return nil, err
+		}
+
+		timers = append(timers, t)
+	}
+
+	return timers, rows.Err()
+}
+
+func (p *psql) GetOneTimer(channel, name string) (database.Timer, error) {
+	row := p.db.QueryRow(statements.GetTimer, channel, name)
+
+	var t database.Timer
+
+	err := row.Scan(&t.ID, &t.Channel, &t.Name, &t.Message, &t.Interval, &t.MinLines, &t.Enabled, &t.Added, &t.Edited)
+
+	return t, err
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/devusSs/twitch-kraken/internal/database/postgres/statements"
 )
 
-func (p *psql) RegisterTwitchUser(username string, firstSeen time.Time) error {
-	row := p.db.QueryRow(statements.RegisterTwitchUser, username, firstSeen, firstSeen)
+func (p *psql) RegisterTwitchUser(channel, username string, firstSeen time.Time) error {
+	row := p.db.QueryRow(statements.RegisterTwitchUser, channel, username, firstSeen, firstSeen)
 
 	var id int
 
@@ -23,8 +23,8 @@ func (p *psql) RegisterTwitchUser(username string, firstSeen time.Time) error {
 	return err
 }
 
-func (p *psql) UpdateTwitchUserDC(username string, lastSeen time.Time) error {
-	row := p.db.QueryRow(statements.UpsertTwitchUserDC, username, lastSeen, lastSeen)
+func (p *psql) UpdateTwitchUserDC(channel, username string, lastSeen time.Time) error {
+	row := p.db.QueryRow(statements.UpsertTwitchUserDC, channel, username, lastSeen, lastSeen)
 
 	var id int
 
@@ -39,7 +39,7 @@ func (p *psql) UpdateTwitchUserDC(username string, lastSeen time.Time) error {
 }
 
 func (p *psql) UpdateTwitchUserBaseDetails(user database.TwitchUser) error {
-	row := p.db.QueryRow(statements.UpsertTwitchUserBaseDetails, user.Username, user.TwitchID, user.DisplayName,
+	row := p.db.QueryRow(statements.UpsertTwitchUserBaseDetails, user.Channel, user.Username, user.TwitchID, user.DisplayName,
 		user.IsMod.Bool, user.LastSeen.Time, user.LastSeen.Time)
 
 	err := row.Scan(&user.ID)
@@ -53,7 +53,7 @@ func (p *psql) UpdateTwitchUserBaseDetails(user database.TwitchUser) error {
 }
 
 func (p *psql) UpdateTwitchUserOnBan(user database.TwitchUser) error {
-	row := p.db.QueryRow(statements.UpsertTwitchUserBanOrTimeout, user.TwitchID, user.Username,
+	row := p.db.QueryRow(statements.UpsertTwitchUserBanOrTimeout, user.Channel, user.TwitchID, user.Username,
 		user.LastSeen.Time, user.HasBeenBanned.Bool, user.LastBan.Time)
 
 	err := row.Scan(&user.ID)
@@ -65,3 +65,19 @@ func (p *psql) UpdateTwitchUserOnBan(user database.TwitchUser) error {
 
 	return err
 }
+
+func (p *psql) GetTwitchUser(channel, username string) (database.TwitchUser, error) {
+	row := p.db.QueryRow(statements.GetTwitchUser, channel, username)
+
+	var u database.TwitchUser
+	var twitchID, displayName sql.NullString
+
+	err := row.Scan(&u.ID, &u.Channel, &twitchID, &u.Username, &displayName, &u.IsMod, &u.FirstSeen,
+		&u.LastSeen, &u.HasBeenBanned, &u.LastBan)
+
+	// Users only seen via JOIN have no twitchid or displayname yet.
+	u.TwitchID = twitchID.String
+	u.DisplayName = displayName.String
+
+	return u, err
+}
@@ -91,15 +91,21 @@ type config struct {
 		Database string `json:"database"`
 	} `json:"postgres"`
 	Twitch struct {
-		BotLogin    string   `json:"bot_login"`
-		BotPassword string   `json:"bot_password"`
+		BotLogin    string `json:"bot_login"`
+		BotPassword string `json:"bot_password"`
+		Channels    []struct {
+			Name    string   `json:"name"`
+			Owner   string   `json:"owner"`
+			Editors []string `json:"editors"`
+		} `json:"channels"`
 		JoinChannel string   `json:"join_channel"`
 		BotOwner    string   `json:"bot_owner"`
 		Editors     []string `json:"editors"`
 	} `json:"twitch"`
 	Command struct {
-		Prefix          string `json:"prefix"`
-		DefaultCooldown int    `json:"default_cooldown"`
+		Prefixes        []string `json:"prefixes"`
+		Prefix          string   `json:"prefix"`
+		DefaultCooldown int      `json:"default_cooldown"`
 	} `json:"command"`
 }
 
@@ -150,16 +156,29 @@ func (c *config) checkConfig() error {
 		return fmt.Errorf("missing key: twitch bot password")
 	}
 
-	if c.Twitch.JoinChannel == "" {
-		return fmt.Errorf("missing key: twitch join channel")
+	// Old single channel setup.
+	if len(c.Twitch.Channels) == 0 {
+		if c.Twitch.JoinChannel == "" {
+			return fmt.Errorf("missing key: twitch channels")
+		}
+
+		if c.Twitch.BotOwner == "" {
+			return fmt.Errorf("missing key: twitch bot owner")
+		}
 	}
 
-	if c.Twitch.BotOwner == "" {
-		return fmt.Errorf("missing key: twitch bot owner")
+	for i, channel := range c.Twitch.Channels {
+		if channel.Name == "" {
+			return fmt.Errorf("missing key: twitch channel name (channel %d)", i)
+		}
+
+		if channel.Owner == "" {
+			return fmt.Errorf("missing key: twitch channel owner (channel %s)", channel.Name)
+		}
 	}
 
-	if c.Command.Prefix == "" {
-		return fmt.Errorf("missing key: command prefix")
+	if len(c.Command.Prefixes) == 0 && c.Command.Prefix == "" {
+		return fmt.Errorf("missing key: command prefixes")
 	}
 
 	return nil
@@ -0,0 +1,53 @@
+package helix
+
+import (
+	"net/http"
+	"net/url"
+)
+
+type ChannelInformation struct {
+	BroadcasterID       string   `json:"broadcaster_id"`
+	BroadcasterLogin    string   `json:"broadcaster_login"`
+	BroadcasterName     string   `json:"broadcaster_name"`
+	BroadcasterLanguage string   `json:"broadcaster_language"`
+	GameID              string   `json:"game_id"`
+	GameName            string   `json:"game_name"`
+	Title               string   `json:"title"`
+	Delay               int      `json:"delay"`
+	Tags                []string `json:"tags"`
+}
+
+// Fields to update via ModifyChannelInformation(), empty fields will not be changed.
+type ModifyChannelParams struct {
+	GameID              string   `json:"game_id,omitempty"`
+	Title               string   `json:"title,omitempty"`
+	BroadcasterLanguage string   `json:"broadcaster_language,omitempty"`
+	Tags                []string `json:"tags,omitempty"`
+}
+
+// Returns the channel information (title, game, ...) of a broadcaster.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-channel-information
+func (c *Client) GetChannelInformation(broadcasterID string) (ChannelInformation, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+
+	channels, _, err := getPage[ChannelInformation](c, "/channels", query)
+	if err != nil {
+		return ChannelInformation{}, err
+	}
+
+	return firstItem(channels)
+}
+
+// Updates the channel information (title, game, ...) of a broadcaster.
+//
+// Needs the channel:manage:broadcast scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#modify-channel-information
+func (c *Client) ModifyChannelInformation(broadcasterID string, params ModifyChannelParams) error {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+
+	return c.do(http.MethodPatch, "/channels", query, params, nil)
+}
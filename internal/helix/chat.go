@@ -0,0 +1,78 @@
+package helix
+
+import (
+	"net/http"
+	"net/url"
+)
+
+type ChatSettings struct {
+	BroadcasterID                 string `json:"broadcaster_id"`
+	EmoteMode                     bool   `json:"emote_mode"`
+	FollowerMode                  bool   `json:"follower_mode"`
+	FollowerModeDuration          *int   `json:"follower_mode_duration"`
+	ModeratorID                   string `json:"moderator_id"`
+	NonModeratorChatDelay         bool   `json:"non_moderator_chat_delay"`
+	NonModeratorChatDelayDuration *int   `json:"non_moderator_chat_delay_duration"`
+	SlowMode                      bool   `json:"slow_mode"`
+	SlowModeWaitTime              *int   `json:"slow_mode_wait_time"`
+	SubscriberMode                bool   `json:"subscriber_mode"`
+	UniqueChatMode                bool   `json:"unique_chat_mode"`
+}
+
+// Chat settings to update via UpdateChatSettings(), nil fields will not be changed.
+type UpdateChatSettingsParams struct {
+	EmoteMode                     *bool `json:"emote_mode,omitempty"`
+	FollowerMode                  *bool `json:"follower_mode,omitempty"`
+	FollowerModeDuration          *int  `json:"follower_mode_duration,omitempty"`
+	NonModeratorChatDelay         *bool `json:"non_moderator_chat_delay,omitempty"`
+	NonModeratorChatDelayDuration *int  `json:"non_moderator_chat_delay_duration,omitempty"`
+	SlowMode                      *bool `json:"slow_mode,omitempty"`
+	SlowModeWaitTime              *int  `json:"slow_mode_wait_time,omitempty"`
+	SubscriberMode                *bool `json:"subscriber_mode,omitempty"`
+	UniqueChatMode                *bool `json:"unique_chat_mode,omitempty"`
+}
+
+// Returns the chat settings (slow mode, sub-only mode, ...) of the broadcaster's chat.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-chat-settings
+func (c *Client) GetChatSettings(broadcasterID string) (ChatSettings, error) {
+	moderatorID, err := c.TokenUserID()
+	if err != nil {
+		return ChatSettings{}, err
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("moderator_id", moderatorID)
+
+	settings, _, err := getPage[ChatSettings](c, "/chat/settings", query)
+	if err != nil {
+		return ChatSettings{}, err
+	}
+
+	return firstItem(settings)
+}
+
+// Updates the chat settings of the broadcaster's chat and returns the new settings.
+//
+// Needs the moderator:manage:chat_settings scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#update-chat-settings
+func (c *Client) UpdateChatSettings(broadcasterID string, params UpdateChatSettingsParams) (ChatSettings, error) {
+	moderatorID, err := c.TokenUserID()
+	if err != nil {
+		return ChatSettings{}, err
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("moderator_id", moderatorID)
+
+	var res response[ChatSettings]
+
+	if err := c.do(http.MethodPatch, "/chat/settings", query, params, &res); err != nil {
+		return ChatSettings{}, err
+	}
+
+	return firstItem(res.Data)
+}
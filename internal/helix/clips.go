@@ -0,0 +1,61 @@
+package helix
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type Clip struct {
+	ID              string    `json:"id"`
+	URL             string    `json:"url"`
+	EmbedURL        string    `json:"embed_url"`
+	BroadcasterID   string    `json:"broadcaster_id"`
+	BroadcasterName string    `json:"broadcaster_name"`
+	CreatorID       string    `json:"creator_id"`
+	CreatorName     string    `json:"creator_name"`
+	VideoID         string    `json:"video_id"`
+	GameID          string    `json:"game_id"`
+	Language        string    `json:"language"`
+	Title           string    `json:"title"`
+	ViewCount       int       `json:"view_count"`
+	CreatedAt       time.Time `json:"created_at"`
+	ThumbnailURL    string    `json:"thumbnail_url"`
+	Duration        float64   `json:"duration"`
+}
+
+// Clip which is still being processed by Twitch, use GetClips() with the ID to check if it is done.
+type CreatedClip struct {
+	ID      string `json:"id"`
+	EditURL string `json:"edit_url"`
+}
+
+// Returns the clips of the broadcaster.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-clips
+func (c *Client) GetClips(broadcasterID string, limit int) ([]Clip, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+
+	return getAll[Clip](c, "/clips", query, limit)
+}
+
+// Creates a clip of the broadcaster's live stream.
+//
+// Needs the clips:edit scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#create-clip
+func (c *Client) CreateClip(broadcasterID string, hasDelay bool) (CreatedClip, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("has_delay", strconv.FormatBool(hasDelay))
+
+	var res response[CreatedClip]
+
+	if err := c.do(http.MethodPost, "/clips", query, nil, &res); err != nil {
+		return CreatedClip{}, err
+	}
+
+	return firstItem(res.Data)
+}
@@ -0,0 +1,31 @@
+package helix
+
+import (
+	"net/url"
+	"time"
+)
+
+type Follower struct {
+	UserID     string    `json:"user_id"`
+	UserLogin  string    `json:"user_login"`
+	UserName   string    `json:"user_name"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// Returns the follow of a user to the broadcaster's channel or ErrNotFound if the user does not follow.
+//
+// Needs the moderator:read:followers scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-channel-followers
+func (c *Client) GetChannelFollower(broadcasterID, userID string) (Follower, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("user_id", userID)
+
+	followers, _, err := getPage[Follower](c, "/channels/followers", query)
+	if err != nil {
+		return Follower{}, err
+	}
+
+	return firstItem(followers)
+}
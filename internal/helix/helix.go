@@ -0,0 +1,314 @@
+// Client for Twitch's Helix API.
+//
+// https://dev.twitch.tv/docs/api/reference/
+package helix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "https://api.twitch.tv/helix"
+
+	// How often a request will be tried before giving up.
+	maxAttempts = 3
+	// Maximum page size of paginated endpoints and maximum ids / logins per request.
+	maxPageSize = 100
+)
+
+// Returned if Twitch did not return the requested resource (user, stream, ...).
+var ErrNotFound = errors.New("helix: not found")
+
+// Helix API client. Use New() to create one.
+type Client struct {
+	baseURL  string
+	clientID string
+	// Returns the current user access token.
+	token func() string
+	// Refreshes the user access token, called once if Twitch rejects the token.
+	refresh func() error
+	http    *http.Client
+
+	// ID of the user the access token belongs to, will be queried once.
+	tokenUserID   string
+	tokenUserIDMu sync.Mutex
+
+	// Rate limit state reported by the Ratelimit-* headers of the last response.
+	rateRemaining int
+	rateReset     time.Time
+	rateMu        sync.Mutex
+}
+
+// Error returned by the Helix API.
+type APIError struct {
+	Status  int    `json:"status"`
+	Err     string `json:"error"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("helix: %d %s: %s", e.Status, e.Err, e.Message)
+}
+
+// Timestamp which Twitch sends as empty string if it is not set (e.g. expires_at of permanent bans).
+type OptionalTime struct {
+	time.Time
+}
+
+func (t *OptionalTime) UnmarshalJSON(data []byte) error {
+	if string(data) == `""` || string(data) == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+	return json.Unmarshal(data, &t.Time)
+}
+
+// Response envelope of Helix endpoints.
+type response[T any] struct {
+	Data       []T `json:"data"`
+	Pagination struct {
+		Cursor string `json:"cursor"`
+	} `json:"pagination"`
+}
+
+// Inits a new Helix client. Uses DefaultBaseURL if baseURL is empty.
+//
+// The token function is called on every request, so refreshed tokens will be picked up automatically.
+func New(baseURL, clientID string, token func() string, refresh func() error) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	c := Client{}
+
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+	c.clientID = clientID
+	c.token = token
+	c.refresh = refresh
+	c.http = &http.Client{Timeout: 10 * time.Second}
+	c.rateRemaining = -1
+
+	return &c
+}
+
+// Returns the Twitch user ID the access token belongs to, needed as moderator ID for moderation endpoints.
+func (c *Client) TokenUserID() (string, error) {
+	c.tokenUserIDMu.Lock()
+	defer c.tokenUserIDMu.Unlock()
+
+	if c.tokenUserID != "" {
+		return c.tokenUserID, nil
+	}
+
+	users, err := c.GetUsers(nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if len(users) == 0 {
+		return "", ErrNotFound
+	}
+
+	c.tokenUserID = users[0].ID
+
+	return c.tokenUserID, nil
+}
+
+// Performs a request against the Helix API and decodes the response into out (may be nil).
+//
+// Retries on rate limits and server errors, refreshes the access token once if it got rejected.
+func (c *Client) do(method, path string, query url.Values, body interface{}, out interface{}) error {
+	var payload []byte
+
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	refreshed := false
+
+	var lastErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		c.waitForRateLimit()
+
+		req, err := http.NewRequest(method, endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+
+		req.Header.Add("Client-Id", c.clientID)
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token()))
+		req.Header.Add("Accept", "application/json")
+		if body != nil {
+			req.Header.Add("Content-Type", "application/json")
+		}
+
+		res, err := c.http.Do(req)
+		if err != nil {
+			lastErr = err
+			time.Sleep(time.Duration(attempt) * time.Second)
+			continue
+		}
+
+		resBody, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		c.updateRateLimit(res.Header)
+
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			if out == nil || len(resBody) == 0 {
+				return nil
+			}
+			return json.Unmarshal(resBody, out)
+		}
+
+		apiErr := &APIError{Status: res.StatusCode}
+		if err := json.Unmarshal(resBody, apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(resBody))
+		}
+		apiErr.Status = res.StatusCode
+		lastErr = apiErr
+
+		switch {
+		case res.StatusCode == http.StatusUnauthorized && !refreshed && c.refresh != nil:
+			// Token might have expired, refresh it once and try again.
+			refreshed = true
+			if err := c.refresh(); err != nil {
+				return err
+			}
+			attempt--
+		case res.StatusCode == http.StatusTooManyRequests:
+			// Waiting is handled by waitForRateLimit() on the next attempt.
+			c.markRateLimited(res.Header)
+		case res.StatusCode >= 500:
+			time.Sleep(time.Duration(attempt) * time.Second)
+		default:
+			return apiErr
+		}
+	}
+
+	return lastErr
+}
+
+// Requests a single page and returns its items and the cursor of the next page (empty on last page).
+func getPage[T any](c *Client, path string, query url.Values) ([]T, string, error) {
+	var res response[T]
+
+	if err := c.do(http.MethodGet, path, query, nil, &res); err != nil {
+		return nil, "", err
+	}
+
+	return res.Data, res.Pagination.Cursor, nil
+}
+
+// Requests pages of a paginated endpoint (first / after) until limit items were collected.
+//
+// Use a limit of 0 to request every page.
+func getAll[T any](c *Client, path string, query url.Values, limit int) ([]T, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+
+	items := []T{}
+
+	for {
+		first := maxPageSize
+		if limit > 0 && limit-len(items) < first {
+			first = limit - len(items)
+		}
+		query.Set("first", strconv.Itoa(first))
+
+		page, cursor, err := getPage[T](c, path, query)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page...)
+
+		if cursor == "" || len(page) == 0 || (limit > 0 && len(items) >= limit) {
+			break
+		}
+
+		query.Set("after", cursor)
+	}
+
+	return items, nil
+}
+
+// Returns the first item of a response or ErrNotFound if the response is empty.
+func firstItem[T any](items []T) (T, error) {
+	if len(items) == 0 {
+		var empty T
+		return empty, ErrNotFound
+	}
+	return items[0], nil
+}
+
+// Blocks until the rate limit bucket has points left (if Twitch told us it is empty).
+func (c *Client) waitForRateLimit() {
+	c.rateMu.Lock()
+	remaining, reset := c.rateRemaining, c.rateReset
+	c.rateMu.Unlock()
+
+	if remaining != 0 {
+		return
+	}
+
+	if wait := time.Until(reset); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// Marks the rate limit bucket as empty until Ratelimit-Reset or for one second if the header is missing.
+func (c *Client) markRateLimited(header http.Header) {
+	reset := time.Now().Add(time.Second)
+
+	if unix, err := strconv.ParseInt(header.Get("Ratelimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(unix, 0)
+	}
+
+	c.rateMu.Lock()
+	c.rateRemaining = 0
+	c.rateReset = reset
+	c.rateMu.Unlock()
+}
+
+// Stores the rate limit state of the Ratelimit-Remaining and Ratelimit-Reset (unix seconds) headers.
+func (c *Client) updateRateLimit(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("Ratelimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	reset, err := strconv.ParseInt(header.Get("Ratelimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	c.rateMu.Lock()
+	c.rateRemaining = remaining
+	c.rateReset = time.Unix(reset, 0)
+	c.rateMu.Unlock()
+}
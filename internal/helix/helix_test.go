@@ -0,0 +1,134 @@
+package helix
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Starts a stub Helix API and returns a client using it.
+func newTestClient(t *testing.T, handler http.HandlerFunc, refresh func() error) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	token := "token"
+	if refresh == nil {
+		refresh = func() error { return nil }
+	}
+
+	return New(server.URL, "client-id", func() string { return token }, refresh)
+}
+
+func TestGetBannedUsersPermanentBan(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/moderation/banned" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"data": [
+			{"user_id": "1", "user_login": "banned", "expires_at": "", "created_at": "2023-07-01T10:00:00Z"},
+			{"user_id": "2", "user_login": "timedout", "expires_at": "2023-07-01T10:10:00Z", "created_at": "2023-07-01T10:00:00Z"}
+		], "pagination": {}}`)
+	}, nil)
+
+	banned, err := c.GetBannedUsers("123")
+	if err != nil {
+		t.Fatalf("GetBannedUsers() error = %v", err)
+	}
+
+	if len(banned) != 2 {
+		t.Fatalf("GetBannedUsers() returned %d users, want 2", len(banned))
+	}
+
+	if !banned[0].ExpiresAt.IsZero() {
+		t.Errorf("permanent ban expires at %v, want zero time", banned[0].ExpiresAt)
+	}
+
+	if banned[1].ExpiresAt.IsZero() {
+		t.Errorf("timeout has no expiry")
+	}
+}
+
+func TestRefreshOnUnauthorized(t *testing.T) {
+	requests, refreshes := 0, 0
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if refreshes == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "Unauthorized", "status": 401, "message": "Invalid OAuth token"}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": "1", "login": "kraken"}]}`)
+	}, func() error {
+		refreshes++
+		return nil
+	})
+
+	user, err := c.GetUserByLogin("kraken")
+	if err != nil {
+		t.Fatalf("GetUserByLogin() error = %v", err)
+	}
+
+	if user.ID != "1" || requests != 2 || refreshes != 1 {
+		t.Errorf("got user %q after %d requests and %d refreshes, want user 1 after 2 requests and 1 refresh",
+			user.ID, requests, refreshes)
+	}
+}
+
+func TestRefreshOnlyOnce(t *testing.T) {
+	refreshes := 0
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}, func() error {
+		refreshes++
+		return nil
+	})
+
+	_, err := c.GetUserByLogin("kraken")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Fatalf("GetUserByLogin() error = %v, want 401 APIError", err)
+	}
+
+	if refreshes != 1 {
+		t.Errorf("token refreshed %d times, want 1", refreshes)
+	}
+}
+
+func TestGetStreamOffline(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [], "pagination": {}}`)
+	}, nil)
+
+	if _, err := c.GetStream("123"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetStream() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetAllPages(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("after") {
+		case "":
+			fmt.Fprint(w, `{"data": [{"user_id": "1"}, {"user_id": "2"}], "pagination": {"cursor": "page2"}}`)
+		case "page2":
+			fmt.Fprint(w, `{"data": [{"user_id": "3"}], "pagination": {}}`)
+		default:
+			t.Errorf("unexpected cursor %s", r.URL.Query().Get("after"))
+		}
+	}, nil)
+
+	moderators, err := c.GetModerators("123")
+	if err != nil {
+		t.Fatalf("GetModerators() error = %v", err)
+	}
+
+	if len(moderators) != 3 {
+		t.Errorf("GetModerators() returned %d moderators, want 3", len(moderators))
+	}
+}
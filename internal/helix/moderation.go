@@ -0,0 +1,129 @@
+package helix
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Bans a user from the broadcaster's chat. Use a duration > 0 (seconds) to time the user out instead.
+//
+// https://dev.twitch.tv/docs/api/reference/#ban-user
+func (c *Client) BanUser(broadcasterID, userID string, duration int, reason string) error {
+	moderatorID, err := c.TokenUserID()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("moderator_id", moderatorID)
+
+	type banData struct {
+		UserID   string `json:"user_id"`
+		Duration int    `json:"duration,omitempty"`
+		Reason   string `json:"reason"`
+	}
+
+	body := struct {
+		Data banData `json:"data"`
+	}{banData{UserID: userID, Duration: duration, Reason: reason}}
+
+	return c.do(http.MethodPost, "/moderation/bans", query, body, nil)
+}
+
+// Removes a ban or timeout of a user.
+//
+// https://dev.twitch.tv/docs/api/reference/#unban-user
+func (c *Client) UnbanUser(broadcasterID, userID string) error {
+	moderatorID, err := c.TokenUserID()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("moderator_id", moderatorID)
+	query.Set("user_id", userID)
+
+	return c.do(http.MethodDelete, "/moderation/bans", query, nil, nil)
+}
+
+// Deletes a single chat message.
+//
+// Needs the moderator:manage:chat_messages scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#delete-chat-messages
+func (c *Client) DeleteChatMessage(broadcasterID, messageID string) error {
+	moderatorID, err := c.TokenUserID()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("moderator_id", moderatorID)
+	query.Set("message_id", messageID)
+
+	return c.do(http.MethodDelete, "/moderation/chat", query, nil, nil)
+}
+
+type BannedUser struct {
+	UserID         string       `json:"user_id"`
+	UserLogin      string       `json:"user_login"`
+	UserName       string       `json:"user_name"`
+	ExpiresAt      OptionalTime `json:"expires_at"` // zero for permanent bans
+	CreatedAt      time.Time    `json:"created_at"`
+	Reason         string       `json:"reason"`
+	ModeratorID    string       `json:"moderator_id"`
+	ModeratorLogin string       `json:"moderator_login"`
+	ModeratorName  string       `json:"moderator_name"`
+}
+
+type Moderator struct {
+	UserID    string `json:"user_id"`
+	UserLogin string `json:"user_login"`
+	UserName  string `json:"user_name"`
+}
+
+// Returns every banned or timed out user of the broadcaster's chat.
+//
+// Needs the moderation:read scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-banned-users
+func (c *Client) GetBannedUsers(broadcasterID string) ([]BannedUser, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+
+	return getAll[BannedUser](c, "/moderation/banned", query, 0)
+}
+
+// Returns the ban or timeout of a single user, ErrNotFound if the user is neither banned nor timed out.
+//
+// Needs the moderation:read scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-banned-users
+func (c *Client) GetBannedUser(broadcasterID, userID string) (BannedUser, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+	query.Set("user_id", userID)
+
+	banned, _, err := getPage[BannedUser](c, "/moderation/banned", query)
+	if err != nil {
+		return BannedUser{}, err
+	}
+
+	return firstItem(banned)
+}
+
+// Returns every moderator of the broadcaster's chat.
+//
+// Needs the moderation:read scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-moderators
+func (c *Client) GetModerators(broadcasterID string) ([]Moderator, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+
+	return getAll[Moderator](c, "/moderation/moderators", query, 0)
+}
@@ -0,0 +1,97 @@
+package helix
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	PollTerminated = "TERMINATED"
+	PollArchived   = "ARCHIVED"
+)
+
+type PollChoice struct {
+	ID                 string `json:"id"`
+	Title              string `json:"title"`
+	Votes              int    `json:"votes"`
+	ChannelPointsVotes int    `json:"channel_points_votes"`
+}
+
+type Poll struct {
+	ID                         string       `json:"id"`
+	BroadcasterID              string       `json:"broadcaster_id"`
+	BroadcasterName            string       `json:"broadcaster_name"`
+	BroadcasterLogin           string       `json:"broadcaster_login"`
+	Title                      string       `json:"title"`
+	Choices                    []PollChoice `json:"choices"`
+	ChannelPointsVotingEnabled bool         `json:"channel_points_voting_enabled"`
+	ChannelPointsPerVote       int          `json:"channel_points_per_vote"`
+	Status                     string       `json:"status"`
+	Duration                   int          `json:"duration"`
+	StartedAt                  time.Time    `json:"started_at"`
+	EndedAt                    *time.Time   `json:"ended_at"`
+}
+
+// Returns the polls of the broadcaster, most recent first.
+//
+// Needs the channel:read:polls or channel:manage:polls scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-polls
+func (c *Client) GetPolls(broadcasterID string, limit int) ([]Poll, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+
+	return getAll[Poll](c, "/polls", query, limit)
+}
+
+// Starts a poll with 2 - 5 choices, duration in seconds (15 - 1800).
+//
+// Needs the channel:manage:polls scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#create-poll
+func (c *Client) CreatePoll(broadcasterID, title string, choices []string, duration int) (Poll, error) {
+	type choice struct {
+		Title string `json:"title"`
+	}
+
+	body := struct {
+		BroadcasterID string   `json:"broadcaster_id"`
+		Title         string   `json:"title"`
+		Choices       []choice `json:"choices"`
+		Duration      int      `json:"duration"`
+	}{BroadcasterID: broadcasterID, Title: title, Duration: duration}
+
+	for _, title := range choices {
+		body.Choices = append(body.Choices, choice{title})
+	}
+
+	var res response[Poll]
+
+	if err := c.do(http.MethodPost, "/polls", nil, body, &res); err != nil {
+		return Poll{}, err
+	}
+
+	return firstItem(res.Data)
+}
+
+// Ends a poll, use PollTerminated to keep showing the results or PollArchived to hide them.
+//
+// Needs the channel:manage:polls scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#end-poll
+func (c *Client) EndPoll(broadcasterID, pollID, status string) (Poll, error) {
+	body := struct {
+		BroadcasterID string `json:"broadcaster_id"`
+		ID            string `json:"id"`
+		Status        string `json:"status"`
+	}{broadcasterID, pollID, status}
+
+	var res response[Poll]
+
+	if err := c.do(http.MethodPatch, "/polls", nil, body, &res); err != nil {
+		return Poll{}, err
+	}
+
+	return firstItem(res.Data)
+}
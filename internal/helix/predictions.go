@@ -0,0 +1,100 @@
+package helix
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	PredictionResolved = "RESOLVED"
+	PredictionCanceled = "CANCELED"
+	PredictionLocked   = "LOCKED"
+)
+
+type PredictionOutcome struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Users         int    `json:"users"`
+	ChannelPoints int    `json:"channel_points"`
+	Color         string `json:"color"`
+}
+
+type Prediction struct {
+	ID               string              `json:"id"`
+	BroadcasterID    string              `json:"broadcaster_id"`
+	BroadcasterName  string              `json:"broadcaster_name"`
+	BroadcasterLogin string              `json:"broadcaster_login"`
+	Title            string              `json:"title"`
+	WinningOutcomeID string              `json:"winning_outcome_id"`
+	Outcomes         []PredictionOutcome `json:"outcomes"`
+	PredictionWindow int                 `json:"prediction_window"`
+	Status           string              `json:"status"`
+	CreatedAt        time.Time           `json:"created_at"`
+	EndedAt          *time.Time          `json:"ended_at"`
+	LockedAt         *time.Time          `json:"locked_at"`
+}
+
+// Returns the predictions of the broadcaster, most recent first.
+//
+// Needs the channel:read:predictions or channel:manage:predictions scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-predictions
+func (c *Client) GetPredictions(broadcasterID string, limit int) ([]Prediction, error) {
+	query := url.Values{}
+	query.Set("broadcaster_id", broadcasterID)
+
+	return getAll[Prediction](c, "/predictions", query, limit)
+}
+
+// Starts a prediction with 2 - 10 outcomes, window in seconds (30 - 1800).
+//
+// Needs the channel:manage:predictions scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#create-prediction
+func (c *Client) CreatePrediction(broadcasterID, title string, outcomes []string, window int) (Prediction, error) {
+	type outcome struct {
+		Title string `json:"title"`
+	}
+
+	body := struct {
+		BroadcasterID    string    `json:"broadcaster_id"`
+		Title            string    `json:"title"`
+		Outcomes         []outcome `json:"outcomes"`
+		PredictionWindow int       `json:"prediction_window"`
+	}{BroadcasterID: broadcasterID, Title: title, PredictionWindow: window}
+
+	for _, title := range outcomes {
+		body.Outcomes = append(body.Outcomes, outcome{title})
+	}
+
+	var res response[Prediction]
+
+	if err := c.do(http.MethodPost, "/predictions", nil, body, &res); err != nil {
+		return Prediction{}, err
+	}
+
+	return firstItem(res.Data)
+}
+
+// Resolves (PredictionResolved, needs winningOutcomeID), cancels or locks a prediction.
+//
+// Needs the channel:manage:predictions scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#end-prediction
+func (c *Client) EndPrediction(broadcasterID, predictionID, status, winningOutcomeID string) (Prediction, error) {
+	body := struct {
+		BroadcasterID    string `json:"broadcaster_id"`
+		ID               string `json:"id"`
+		Status           string `json:"status"`
+		WinningOutcomeID string `json:"winning_outcome_id,omitempty"`
+	}{broadcasterID, predictionID, status, winningOutcomeID}
+
+	var res response[Prediction]
+
+	if err := c.do(http.MethodPatch, "/predictions", nil, body, &res); err != nil {
+		return Prediction{}, err
+	}
+
+	return firstItem(res.Data)
+}
@@ -0,0 +1,19 @@
+package helix
+
+import "net/url"
+
+type Category struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	BoxArtURL string `json:"box_art_url"`
+}
+
+// Returns up to limit categories (games) matching the query, ordered by relevance.
+//
+// https://dev.twitch.tv/docs/api/reference/#search-categories
+func (c *Client) SearchCategories(query string, limit int) ([]Category, error) {
+	values := url.Values{}
+	values.Set("query", query)
+
+	return getAll[Category](c, "/search/categories", values, limit)
+}
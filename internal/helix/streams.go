@@ -0,0 +1,78 @@
+package helix
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Stream struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	UserLogin    string    `json:"user_login"`
+	UserName     string    `json:"user_name"`
+	GameID       string    `json:"game_id"`
+	GameName     string    `json:"game_name"`
+	Type         string    `json:"type"`
+	Title        string    `json:"title"`
+	Tags         []string  `json:"tags"`
+	ViewerCount  int       `json:"viewer_count"`
+	StartedAt    time.Time `json:"started_at"`
+	Language     string    `json:"language"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	IsMature     bool      `json:"is_mature"`
+}
+
+type StreamMarker struct {
+	ID              string    `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	Description     string    `json:"description"`
+	PositionSeconds int       `json:"position_seconds"`
+}
+
+// Returns the live streams of the specified users (ids and / or logins, up to 100 combined).
+//
+// https://dev.twitch.tv/docs/api/reference/#get-streams
+func (c *Client) GetStreams(userIDs, userLogins []string) ([]Stream, error) {
+	query := url.Values{}
+	query.Set("type", "live")
+
+	for _, id := range userIDs {
+		query.Add("user_id", id)
+	}
+
+	for _, login := range userLogins {
+		query.Add("user_login", login)
+	}
+
+	return getAll[Stream](c, "/streams", query, 0)
+}
+
+// Returns the live stream of a user or ErrNotFound if the user is offline.
+func (c *Client) GetStream(userID string) (Stream, error) {
+	streams, err := c.GetStreams([]string{userID}, nil)
+	if err != nil {
+		return Stream{}, err
+	}
+	return firstItem(streams)
+}
+
+// Adds a marker to the live stream of a user.
+//
+// Needs the channel:manage:broadcast scope.
+//
+// https://dev.twitch.tv/docs/api/reference/#create-stream-marker
+func (c *Client) CreateStreamMarker(userID, description string) (StreamMarker, error) {
+	body := struct {
+		UserID      string `json:"user_id"`
+		Description string `json:"description,omitempty"`
+	}{userID, description}
+
+	var res response[StreamMarker]
+
+	if err := c.do(http.MethodPost, "/streams/markers", nil, body, &res); err != nil {
+		return StreamMarker{}, err
+	}
+
+	return firstItem(res.Data)
+}
@@ -0,0 +1,65 @@
+package helix
+
+import (
+	"net/url"
+	"time"
+)
+
+type User struct {
+	ID              string    `json:"id"`
+	Login           string    `json:"login"`
+	DisplayName     string    `json:"display_name"`
+	Type            string    `json:"type"`
+	BroadcasterType string    `json:"broadcaster_type"`
+	Description     string    `json:"description"`
+	ProfileImageURL string    `json:"profile_image_url"`
+	OfflineImageURL string    `json:"offline_image_url"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Returns the users with the specified ids and / or logins.
+//
+// Returns the user the access token belongs to if no ids or logins are specified.
+//
+// https://dev.twitch.tv/docs/api/reference/#get-users
+func (c *Client) GetUsers(ids, logins []string) ([]User, error) {
+	if len(ids) == 0 && len(logins) == 0 {
+		users, _, err := getPage[User](c, "/users", nil)
+		return users, err
+	}
+
+	users := []User{}
+
+	// Twitch accepts up to 100 ids and logins combined per request.
+	for len(ids) > 0 || len(logins) > 0 {
+		query := url.Values{}
+
+		for i := 0; i < maxPageSize && (len(ids) > 0 || len(logins) > 0); i++ {
+			if len(ids) > 0 {
+				query.Add("id", ids[0])
+				ids = ids[1:]
+			} else {
+				query.Add("login", logins[0])
+				logins = logins[1:]
+			}
+		}
+
+		page, _, err := getPage[User](c, "/users", query)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, page...)
+	}
+
+	return users, nil
+}
+
+// Returns the user with the specified login or ErrNotFound.
+func (c *Client) GetUserByLogin(login string) (User, error) {
+	users, err := c.GetUsers(nil, []string{login})
+	if err != nil {
+		return User{}, err
+	}
+	return firstItem(users)
+}
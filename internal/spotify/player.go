@@ -0,0 +1,167 @@
+package spotify
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Artist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Album struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Track or podcast episode, episodes have a Show instead of Artists and an Album.
+type Track struct {
+	ID           string   `json:"id"`
+	URI          string   `json:"uri"`
+	Name         string   `json:"name"`
+	DurationMS   int      `json:"duration_ms"`
+	Artists      []Artist `json:"artists"`
+	Album        Album    `json:"album"`
+	ExternalURLs struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+	Show struct {
+		Name string `json:"name"`
+	} `json:"show"`
+}
+
+// Returns the names of the artists, or the show of an episode, e.g. "Artist 1, Artist 2".
+func (t Track) ArtistNames() string {
+	if len(t.Artists) == 0 {
+		return t.Show.Name
+	}
+
+	names := []string{}
+	for _, artist := range t.Artists {
+		names = append(names, artist.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Returns the track formatted for chat, e.g. "Title by Artist 1, Artist 2".
+func (t Track) String() string {
+	if artists := t.ArtistNames(); artists != "" {
+		return t.Name + " by " + artists
+	}
+	return t.Name
+}
+
+// Returns the length of the track.
+func (t Track) Duration() time.Duration {
+	return time.Duration(t.DurationMS) * time.Millisecond
+}
+
+// Currently playing track and how far it has been played.
+type Playing struct {
+	Track       Track
+	Progress    time.Duration
+	IsPlaying   bool
+	RetrievedAt time.Time
+}
+
+type currentlyPlaying struct {
+	IsPlaying            bool   `json:"is_playing"`
+	ProgressMS           int    `json:"progress_ms"`
+	CurrentlyPlayingType string `json:"currently_playing_type"` // track, episode, ad or unknown
+	Item                 *Track `json:"item"`
+}
+
+// Returns the track playing right now, ErrNothingPlaying or ErrAdPlaying.
+//
+// The result is cached for a few seconds, so chat can spam !song without hitting the rate limits.
+//
+// Needs the user-read-currently-playing scope.
+//
+// https://developer.spotify.com/documentation/web-api/reference/get-the-users-currently-playing-track
+func (c *Client) CurrentlyPlaying() (Playing, error) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if !c.cachedTime.IsZero() && time.Since(c.cachedTime) < cacheTTL {
+		return c.cached, c.cachedErr
+	}
+
+	playing, err := c.currentlyPlaying()
+
+	// Do not cache errors like lost connections, only the actual state.
+	if err == nil || err == ErrNothingPlaying || err == ErrAdPlaying {
+		c.cached, c.cachedErr, c.cachedTime = playing, err, time.Now()
+	}
+
+	return playing, err
+}
+
+// Drops the cached currently playing track, e.g. after skipping.
+func (c *Client) invalidateCache() {
+	c.cacheMu.Lock()
+	c.cachedTime = time.Time{}
+	c.cacheMu.Unlock()
+}
+
+func (c *Client) currentlyPlaying() (Playing, error) {
+	query := url.Values{}
+	query.Set("additional_types", "track,episode")
+
+	var res currentlyPlaying
+
+	status, err := c.do(http.MethodGet, "/me/player/currently-playing", query, nil, &res)
+	if err != nil {
+		return Playing{}, err
+	}
+
+	// Spotify answers 204 No Content if nothing is playing.
+	if status == http.StatusNoContent {
+		return Playing{}, ErrNothingPlaying
+	}
+
+	if res.CurrentlyPlayingType == "ad" {
+		return Playing{}, ErrAdPlaying
+	}
+
+	if res.Item == nil || !res.IsPlaying {
+		return Playing{}, ErrNothingPlaying
+	}
+
+	playing := Playing{}
+	playing.Track = *res.Item
+	playing.Progress = time.Duration(res.ProgressMS) * time.Millisecond
+	playing.IsPlaying = res.IsPlaying
+	playing.RetrievedAt = time.Now()
+
+	return playing, nil
+}
+
+// Track of the recently played history.
+type PlayedTrack struct {
+	Track    Track     `json:"track"`
+	PlayedAt time.Time `json:"played_at"`
+}
+
+// Returns the last played tracks, newest first. Tracks only show up after they were played for at least 30 seconds.
+//
+// Needs the user-read-recently-played scope.
+//
+// https://developer.spotify.com/documentation/web-api/reference/get-recently-played
+func (c *Client) RecentlyPlayed(limit int) ([]PlayedTrack, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+
+	var res struct {
+		Items []PlayedTrack `json:"items"`
+	}
+
+	if _, err := c.do(http.MethodGet, "/me/player/recently-played", query, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return res.Items, nil
+}
@@ -0,0 +1,180 @@
+// Client for Spotify's Web API.
+//
+// https://developer.spotify.com/documentation/web-api
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "https://api.spotify.com/v1"
+
+	// How often a request will be tried before giving up.
+	maxAttempts = 3
+	// How long the currently playing track is cached.
+	cacheTTL = 10 * time.Second
+)
+
+var (
+	// Returned if nothing is playing right now (or playback is paused).
+	ErrNothingPlaying = errors.New("spotify: nothing playing")
+	// Returned if an ad is playing right now.
+	ErrAdPlaying = errors.New("spotify: ad playing")
+	// Returned if Spotify did not return the requested resource.
+	ErrNotFound = errors.New("spotify: not found")
+)
+
+// Spotify Web API client. Use New() to create one.
+type Client struct {
+	baseURL string
+	// Returns the current user access token.
+	token func() string
+	// Refreshes the user access token, called once if Spotify rejects the token.
+	refresh func() error
+	http    *http.Client
+
+	// Cached result of CurrentlyPlaying().
+	cached     Playing
+	cachedErr  error
+	cachedTime time.Time
+	cacheMu    sync.Mutex
+}
+
+// Error returned by the Spotify API.
+type APIError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("spotify: %d: %s", e.Status, e.Message)
+}
+
+// Error envelope of the Spotify API.
+type errorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// Inits a new Spotify client. Uses DefaultBaseURL if baseURL is empty.
+//
+// The token function is called on every request, so refreshed tokens will be picked up automatically.
+func New(baseURL string, token func() string, refresh func() error) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	c := Client{}
+
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+	c.token = token
+	c.refresh = refresh
+	c.http = &http.Client{Timeout: 10 * time.Second}
+
+	return &c
+}
+
+// Performs a request against the Spotify API and decodes the response into out (may be nil).
+//
+// Returns the status code of the response, e.g. to detect 204 No Content.
+// Retries on rate limits and server errors, refreshes the access token once if it got rejected.
+func (c *Client) do(method, path string, query url.Values, body interface{}, out interface{}) (int, error) {
+	var payload []byte
+
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	refreshed := false
+
+	var lastErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		req, err := http.NewRequest(method, endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return 0, err
+		}
+
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token()))
+		req.Header.Add("Accept", "application/json")
+		if body != nil {
+			req.Header.Add("Content-Type", "application/json")
+		}
+
+		res, err := c.http.Do(req)
+		if err != nil {
+			lastErr = err
+			time.Sleep(time.Duration(attempt) * time.Second)
+			continue
+		}
+
+		resBody, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return res.StatusCode, err
+		}
+
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			if out == nil || len(resBody) == 0 {
+				return res.StatusCode, nil
+			}
+			return res.StatusCode, json.Unmarshal(resBody, out)
+		}
+
+		var errRes errorResponse
+		apiErr := &errRes.Error
+		if err := json.Unmarshal(resBody, &errRes); err != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(resBody))
+		}
+		apiErr.Status = res.StatusCode
+		lastErr = apiErr
+
+		switch {
+		case res.StatusCode == http.StatusUnauthorized && !refreshed && c.refresh != nil:
+			// Token might have expired, refresh it once and try again.
+			refreshed = true
+			if err := c.refresh(); err != nil {
+				return res.StatusCode, err
+			}
+			attempt--
+		case res.StatusCode == http.StatusTooManyRequests:
+			time.Sleep(retryAfter(res.Header))
+		case res.StatusCode == http.StatusNotFound:
+			return res.StatusCode, ErrNotFound
+		case res.StatusCode >= 500:
+			time.Sleep(time.Duration(attempt) * time.Second)
+		default:
+			return res.StatusCode, apiErr
+		}
+	}
+
+	return 0, lastErr
+}
+
+// Returns how long to wait after a 429 response, Retry-After (seconds) or one second if the header is missing.
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 1 {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
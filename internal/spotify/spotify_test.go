@@ -0,0 +1,144 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Starts a stub Spotify API and returns a client using it.
+func newTestClient(t *testing.T, handler http.HandlerFunc, token func() string, refresh func() error) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return New(server.URL, token, refresh)
+}
+
+func TestRefreshOnUnauthorized(t *testing.T) {
+	token := "expired"
+	refreshes := 0
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": {"status": 401, "message": "The access token expired"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "abc", "name": "Song", "artists": [{"name": "Artist"}]}`)
+	}, func() string { return token }, func() error {
+		refreshes++
+		token = "fresh"
+		return nil
+	})
+
+	track, err := c.GetTrack("abc")
+	if err != nil {
+		t.Fatalf("GetTrack() error = %v", err)
+	}
+
+	if track.String() != "Song by Artist" || refreshes != 1 {
+		t.Errorf("got %q after %d refreshes, want \"Song by Artist\" after 1 refresh", track, refreshes)
+	}
+}
+
+func TestRefreshOnlyOnce(t *testing.T) {
+	refreshes := 0
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error": {"status": 401, "message": "Invalid access token"}}`)
+	}, func() string { return "invalid" }, func() error {
+		refreshes++
+		return nil
+	})
+
+	_, err := c.GetTrack("abc")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized || apiErr.Message != "Invalid access token" {
+		t.Fatalf("GetTrack() error = %v, want 401 APIError", err)
+	}
+
+	if refreshes != 1 {
+		t.Errorf("token refreshed %d times, want 1", refreshes)
+	}
+}
+
+func TestRefreshError(t *testing.T) {
+	refreshErr := errors.New("refresh token revoked")
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}, func() string { return "expired" }, func() error { return refreshErr })
+
+	if _, err := c.GetTrack("abc"); !errors.Is(err, refreshErr) {
+		t.Errorf("GetTrack() error = %v, want the refresh error", err)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": {"status": 404, "message": "Non existing id"}}`)
+	}, func() string { return "token" }, nil)
+
+	if _, err := c.GetTrack("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTrack() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCurrentlyPlaying(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{"nothing playing", http.StatusNoContent, "", ErrNothingPlaying},
+		{"paused", http.StatusOK, `{"is_playing": false, "currently_playing_type": "track", "item": {"id": "abc"}}`, ErrNothingPlaying},
+		{"ad", http.StatusOK, `{"is_playing": true, "currently_playing_type": "ad"}`, ErrAdPlaying},
+		{"track", http.StatusOK, `{"is_playing": true, "progress_ms": 1500, "currently_playing_type": "track", "item": {"id": "abc"}}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}, func() string { return "token" }, nil)
+
+			playing, err := c.CurrentlyPlaying()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CurrentlyPlaying() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && (playing.Track.ID != "abc" || playing.Progress.Milliseconds() != 1500) {
+				t.Errorf("CurrentlyPlaying() = %+v, want track abc at 1.5s", playing)
+			}
+		})
+	}
+}
+
+func TestParseTrackID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", "4uLU6hMCjMI75M1A2tKUQC", true},
+		{"https://open.spotify.com/intl-de/track/4uLU6hMCjMI75M1A2tKUQC?si=abc", "4uLU6hMCjMI75M1A2tKUQC", true},
+		{"spotify:track:4uLU6hMCjMI75M1A2tKUQC", "4uLU6hMCjMI75M1A2tKUQC", true},
+		{"never gonna give you up", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseTrackID(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseTrackID(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package spotify
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// Matches track links (https://open.spotify.com/track/<id>, also with locale like /intl-de/) and URIs (spotify:track:<id>).
+var trackIDRegex = regexp.MustCompile(`^(?:https?://open\.spotify\.com/(?:[a-z-]+/)?track/|spotify:track:)([A-Za-z0-9]{22})`)
+
+// Returns the track ID of a Spotify track link or URI, false if the input is neither.
+func ParseTrackID(input string) (string, bool) {
+	match := trackIDRegex.FindStringSubmatch(input)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// Returns the track with the specified ID or ErrNotFound.
+//
+// https://developer.spotify.com/documentation/web-api/reference/get-track
+func (c *Client) GetTrack(id string) (Track, error) {
+	var track Track
+
+	if _, err := c.do(http.MethodGet, "/tracks/"+url.PathEscape(id), nil, nil, &track); err != nil {
+		return Track{}, err
+	}
+
+	return track, nil
+}
+
+// Returns the best matching tracks of a search, ErrNotFound if nothing matched.
+//
+// https://developer.spotify.com/documentation/web-api/reference/search
+func (c *Client) SearchTracks(search string, limit int) ([]Track, error) {
+	query := url.Values{}
+	query.Set("q", search)
+	query.Set("type", "track")
+	query.Set("limit", strconv.Itoa(limit))
+
+	var res struct {
+		Tracks struct {
+			Items []Track `json:"items"`
+		} `json:"tracks"`
+	}
+
+	if _, err := c.do(http.MethodGet, "/search", query, nil, &res); err != nil {
+		return nil, err
+	}
+
+	if len(res.Tracks.Items) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return res.Tracks.Items, nil
+}
+
+// Adds a track (URI) to the end of the playback queue.
+//
+// Needs the user-modify-playback-state scope and Spotify Premium.
+//
+// https://developer.spotify.com/documentation/web-api/reference/add-to-queue
+func (c *Client) AddToQueue(uri string) error {
+	query := url.Values{}
+	query.Set("uri", uri)
+
+	_, err := c.do(http.MethodPost, "/me/player/queue", query, nil, nil)
+	return err
+}
+
+// Skips to the next track of the playback queue.
+//
+// Needs the user-modify-playback-state scope and Spotify Premium.
+//
+// https://developer.spotify.com/documentation/web-api/reference/skip-users-playback-to-next-track
+func (c *Client) SkipToNext() error {
+	_, err := c.do(http.MethodPost, "/me/player/next", nil, nil, nil)
+	if err == nil {
+		c.invalidateCache()
+	}
+	return err
+}
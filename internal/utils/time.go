@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formats a duration for chat using its two largest units, e.g. "2h 5m", "3d 4h" or "42s".
+func FormatDuration(d time.Duration) string {
+	if d < time.Second {
+		return "0s"
+	}
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	parts := []string{}
+
+	for _, unit := range units {
+		if d < unit.size {
+			// Skip leading zero units, but stop at the first zero unit after a non-zero one.
+			if len(parts) > 0 {
+				break
+			}
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%d%s", d/unit.size, unit.suffix))
+		d %= unit.size
+
+		if len(parts) == 2 {
+			break
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Formats the time since from for chat using its two largest calendar units, e.g. "2y 3mo", "5mo 12d" or "3d 4h".
+//
+// Spans shorter than a month are formatted like FormatDuration.
+func FormatSince(from, now time.Time) string {
+	years := 0
+	for !from.AddDate(years+1, 0, 0).After(now) {
+		years++
+	}
+
+	months := 0
+	for !from.AddDate(years, months+1, 0).After(now) {
+		months++
+	}
+
+	if years == 0 && months == 0 {
+		return FormatDuration(now.Sub(from))
+	}
+
+	days := 0
+	for !from.AddDate(years, months, days+1).After(now) {
+		days++
+	}
+
+	switch {
+	case years > 0 && months > 0:
+		return fmt.Sprintf("%dy %dmo", years, months)
+	case years > 0:
+		return fmt.Sprintf("%dy", years)
+	case days > 0:
+		return fmt.Sprintf("%dmo %dd", months, days)
+	default:
+		return fmt.Sprintf("%dmo", months)
+	}
+}